@@ -0,0 +1,39 @@
+package api
+
+// Diff shows what changed between two crawls of the same site.
+type Diff struct {
+	Added            []URL          `json:"added"`
+	Removed          []URL          `json:"removed"`
+	StatusChanges    []StatusChange `json:"statusChanges"`
+	BrokenLinks      []BrokenLink   `json:"brokenLinks"`
+	TitleChanges     []Change       `json:"titleChanges"`
+	CanonicalChanges []Change       `json:"canonicalChanges"`
+	Redirects        []Redirect     `json:"redirects"`
+}
+
+// StatusChange shows a page which responded with a different status code.
+type StatusChange struct {
+	URL URL `json:"url"`
+	Old int `json:"old"`
+	New int `json:"new"`
+}
+
+// BrokenLink shows a page which is newly broken, and the page that linked to it.
+type BrokenLink struct {
+	URL        URL `json:"url"`
+	Referrer   URL `json:"referrer"`
+	StatusCode int `json:"statusCode"`
+}
+
+// Change shows a value of a page which differs between two crawls.
+type Change struct {
+	URL URL    `json:"url"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Redirect shows a page which now redirects, and where to.
+type Redirect struct {
+	URL    URL `json:"url"`
+	Target URL `json:"target"`
+}
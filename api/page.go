@@ -6,9 +6,56 @@ import (
 
 // Page shows which URLs were found on a given URL.
 type Page struct {
-	URL       URL       `json:"url"`
-	Referrer  URL       `json:"referrer"`
-	CrawledAt time.Time `json:"crawledAt"`
+	URL          URL       `json:"url"`
+	Referrer     URL       `json:"referrer"`
+	Seed         URL       `json:"seed"`
+	Source       string    `json:"source"`
+	Linked       bool      `json:"linked"`
+	Depth        int       `json:"depth"`
+	CrawledAt    time.Time `json:"crawledAt"`
+	FetchSeconds float64   `json:"fetchSeconds"`
+	StatusCode   int       `json:"statusCode"`
+	Redirects    []URL     `json:"redirects,omitempty"`
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Unchanged    bool      `json:"unchanged"`
+	Pending      bool      `json:"pending,omitempty"`
+	Truncated    bool      `json:"truncated"`
+	SkipReason   string    `json:"skipReason,omitempty"`
+	Metadata     Metadata  `json:"metadata"`
+	ContentHash  string    `json:"contentHash,omitempty"`
+	SimHash      string    `json:"simHash,omitempty"`
+	Outlinks     []Link    `json:"outlinks,omitempty"`
+	Links        []URL     `json:"links,omitempty"`
+}
+
+// Link shows an anchor found on a page, along with how it was presented.
+type Link struct {
+	URL      URL      `json:"url"`
+	Text     string   `json:"text"`
+	Title    string   `json:"title,omitempty"`
+	Rel      []string `json:"rel,omitempty"`
+	Target   string   `json:"target,omitempty"`
+	Location string   `json:"location"`
+}
+
+// Metadata shows what a HTML page says about itself.
+type Metadata struct {
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Canonical   *URL        `json:"canonical,omitempty"`
+	Alternates  []Alternate `json:"alternates,omitempty"`
+	H1          []string    `json:"h1,omitempty"`
+	H2          []string    `json:"h2,omitempty"`
+	Lang        string      `json:"lang,omitempty"`
+	WordCount   int         `json:"wordCount"`
+}
+
+// Alternate shows a translation of a page, given by a hreflang link.
+type Alternate struct {
+	Lang string `json:"lang"`
+	URL  URL    `json:"url"`
 }
 
 // URL is a JSON representation of url.URL.
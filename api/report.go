@@ -0,0 +1,69 @@
+package api
+
+import "time"
+
+// Report shows every Page found by a crawl along with any reports derived from them.
+type Report struct {
+	Pages        []Page        `json:"pages"`
+	Orphans      []URL         `json:"orphans"`
+	Findings     []Finding     `json:"findings"`
+	Duplicates   []Duplicates  `json:"duplicates"`
+	Changes      *Changes      `json:"changes,omitempty"`
+	Certificates []Certificate `json:"certificates"`
+	Errors       []CrawlError  `json:"errors"`
+	Thresholds   []Threshold   `json:"thresholds"`
+}
+
+// Threshold shows a limit on a problem found by the crawl, what was measured and the pages with the problem.
+type Threshold struct {
+	Name     string `json:"name"`
+	Limit    int    `json:"limit"`
+	Actual   int    `json:"actual"`
+	Exceeded bool   `json:"exceeded"`
+	URLs     []URL  `json:"urls"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// CrawlError shows a URL which could not be crawled, the phase of crawling it failed in and why.
+type CrawlError struct {
+	URL      URL    `json:"url"`
+	Referrer *URL   `json:"referrer,omitempty"`
+	Phase    string `json:"phase"`
+	Status   int    `json:"status,omitempty"`
+	Cause    string `json:"cause"`
+}
+
+// Certificate shows the TLS certificate presented by a host, and when it expires.
+type Certificate struct {
+	Host          string    `json:"host"`
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	DNSNames      []string  `json:"dnsNames,omitempty"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+	TLSVersion    string    `json:"tlsVersion"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Expired       bool      `json:"expired"`
+}
+
+// Changes shows which pages were added, changed, unchanged or removed since the previous crawl.
+type Changes struct {
+	Added     []URL `json:"added"`
+	Changed   []URL `json:"changed"`
+	Unchanged []URL `json:"unchanged"`
+	Removed   []URL `json:"removed"`
+}
+
+// Duplicates shows a group of pages whose main text is the same, or nearly the same if not exact.
+type Duplicates struct {
+	Exact bool  `json:"exact"`
+	URLs  []URL `json:"urls"`
+}
+
+// Finding shows a problem found with a page by an audit rule.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	URL      URL    `json:"url"`
+	Message  string `json:"message"`
+}
@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"crawler/internal/pkg/diff"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmd associates the diff command with a comparison of two crawls.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "diff reports what changed between two crawls, given their JSON output or cache files",
+		Args:  cobra.ExactArgs(2),
+		RunE:  Run,
+	}
+
+	flags := cmd.Flags()
+	flags.String("format", string(diff.Text), `the format of the differences ["text","json","markdown"]`)
+	flags.String("output", "", "the file the differences are written to, otherwise they are printed")
+
+	return cmd
+}
+
+// Run compares the crawl saved to the first argument with the crawl saved to the second.
+func Run(cmd *cobra.Command, args []string) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	old, err := load(args[0])
+	if err != nil {
+		return err
+	}
+
+	current, err := load(args[1])
+	if err != nil {
+		return err
+	}
+
+	content, err := diff.Print(diff.Compare(old, current), diff.Format(format))
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		fmt.Print(content)
+
+		return nil
+	}
+
+	return os.WriteFile(output, []byte(content), 0600)
+}
@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"bytes"
+	"crawler/api"
+	"crawler/internal/crawler"
+	"crawler/internal/domain"
+	"crawler/storage/file"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// ErrFailedToLoad is returned if a crawl cannot be read.
+var (
+	ErrFailedToLoad = errors.New("failed to load crawl")
+)
+
+// load reads the pages of a crawl from the JSON output of the crawl command, which is either a list of pages or, with
+// jsonReport, an object, or from a cache file, which is a list of stored pages.
+func load(path string) ([]domain.Page, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToLoad)
+	}
+
+	if isCache(b) {
+		stored := file.New(path)
+
+		err = stored.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		return crawler.NewRepository(stored).All(), nil
+	}
+
+	var presentationPages []api.Page
+
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		var report api.Report
+
+		err = json.Unmarshal(b, &report)
+		presentationPages = report.Pages
+	} else {
+		err = json.Unmarshal(b, &presentationPages)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v: %w", path, err, ErrFailedToLoad)
+	}
+
+	pages := make([]domain.Page, len(presentationPages))
+
+	for i := range presentationPages {
+		pages[i] = adaptPresentationPageToDomain(presentationPages[i])
+	}
+
+	return pages, nil
+}
+
+// isCache reports whether b is a cache file, whose pages have the field names of a storage.Page, rather than a list
+// of api.Page's. Both are lists, and JSON field names are matched case insensitively, so the names are compared.
+func isCache(b []byte) bool {
+	var pages []map[string]json.RawMessage
+
+	err := json.Unmarshal(b, &pages)
+	if err != nil || len(pages) == 0 {
+		return false
+	}
+
+	_, ok := pages[0]["URL"]
+
+	return ok
+}
+
+// adaptPresentationPageToDomain adapts the fields of an api.Page which are compared between crawls.
+func adaptPresentationPageToDomain(p api.Page) domain.Page {
+	page := domain.Page{
+		URL:         adaptPresentationURLToDomain(p.URL),
+		Referrer:    adaptPresentationURLToDomain(p.Referrer),
+		StatusCode:  p.StatusCode,
+		ContentType: p.ContentType,
+		Unchanged:   p.Unchanged,
+		Pending:     p.Pending,
+		Metadata: domain.Metadata{
+			Title: p.Metadata.Title,
+		},
+		ContentHash: p.ContentHash,
+	}
+
+	if p.Metadata.Canonical != nil {
+		page.Metadata.Canonical = adaptPresentationURLToDomain(*p.Metadata.Canonical)
+	}
+
+	for _, u := range p.Redirects {
+		page.Redirects = append(page.Redirects, adaptPresentationURLToDomain(u))
+	}
+
+	return page
+}
+
+func adaptPresentationURLToDomain(u api.URL) url.URL {
+	return url.URL{
+		Scheme:      u.Scheme,
+		Opaque:      u.Opaque,
+		Host:        u.Host,
+		Path:        u.Path,
+		RawPath:     u.RawPath,
+		ForceQuery:  u.ForceQuery,
+		RawQuery:    u.RawQuery,
+		Fragment:    u.Fragment,
+		RawFragment: u.RawFragment,
+	}
+}
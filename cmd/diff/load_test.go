@@ -0,0 +1,101 @@
+package diff
+
+import (
+	"crawler/internal/domain"
+	"crawler/storage"
+	"crawler/storage/file"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenCrawl    string
+		expectedPages []domain.Page
+	}{
+		{
+			name: "given a list of pages output by the crawl command, expect its pages",
+			givenCrawl: `[
+				{"url": {"scheme": "https", "host": "example.com", "path": "/"}, "statusCode": 200,
+					"metadata": {"title": "Home"}, "simHash": "00000000000000ff"},
+				{"url": {"scheme": "https", "host": "example.com", "path": "/queued/"}, "pending": true}
+			]`,
+			expectedPages: []domain.Page{
+				{
+					URL:        url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+					StatusCode: http.StatusOK,
+					Metadata:   domain.Metadata{Title: "Home"},
+				},
+				{URL: url.URL{Scheme: "https", Host: "example.com", Path: "/queued/"}, Pending: true},
+			},
+		},
+		{
+			name: "given a report output by the crawl command, expect its pages",
+			givenCrawl: `{"pages": [{"url": {"scheme": "https", "host": "example.com", "path": "/"}, "statusCode": 404}],
+				"orphans": []}`,
+			expectedPages: []domain.Page{
+				{URL: url.URL{Scheme: "https", Host: "example.com", Path: "/"}, StatusCode: http.StatusNotFound},
+			},
+		},
+		{
+			name:       "given an empty list of pages, expect no pages",
+			givenCrawl: `[]`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "output.json")
+
+			err := os.WriteFile(path, []byte(test.givenCrawl), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedPages, cmpopts.EquateEmpty()) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages, cmpopts.EquateEmpty()))
+			}
+		})
+	}
+}
+
+func TestLoad_Cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache := file.New(path)
+
+	err := cache.Insert(storage.Page{
+		URL:        url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+		StatusCode: http.StatusOK,
+		Metadata:   storage.Metadata{Title: "Home"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cache.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actual) != 1 || actual[0].URL.Path != "/" || actual[0].StatusCode != http.StatusOK ||
+		actual[0].Metadata.Title != "Home" {
+		t.Fatalf("expected the cached page, got %v", actual)
+	}
+}
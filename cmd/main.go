@@ -1,33 +1,91 @@
 package main
 
 import (
+	"crawler/cmd/crawl"
+	"crawler/cmd/diff"
 	"crawler/cmd/serve"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var rootCmd = &cobra.Command{}
+var (
+	cfgFile string
+	// logSettings maps each log flag to the setting it overrides.
+	logSettings = map[string]string{
+		"log-level":  "log.level",
+		"log-format": "log.format",
+		"log-file":   "log.file",
+	}
+	rootCmd = &cobra.Command{
+		Use:               "crawler",
+		PersistentPreRunE: bindLogFlags,
+	}
+)
 
-// init adds the serve command to the chain of available commands.
+// init adds the serve, crawl and diff commands to the chain of available commands.
 func init() {
+	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./settings.yaml)")
+	rootCmd.PersistentFlags().String("log-level", "info",
+		`the least severe logs written, "debug" logs every URL ["debug","info","warn","error"]`)
+	rootCmd.PersistentFlags().String("log-format", "text", `the format of the logs ["text","json"]`)
+	rootCmd.PersistentFlags().String("log-file", "", "the file logs are appended to, defaults to stderr")
+
 	rootCmd.AddCommand(serve.NewCmd())
+	rootCmd.AddCommand(crawl.NewCmd())
+	rootCmd.AddCommand(diff.NewCmd())
 }
 
-// main sets the path to the config file and executes the command chain found in the root command.
-func main() {
-	viper.SetConfigName("settings")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./")
+// initConfig sets the path to the config file and allows every setting to be overridden by a CRAWLER_ env var.
+func initConfig() {
+	if cfgFile != "" {
+		viper.SetConfigFile(cfgFile)
+	} else {
+		viper.SetConfigName("settings")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath("./")
+	}
+
+	viper.SetEnvPrefix("crawler")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	}
+}
+
+// bindLogFlags lets the log flags, if they were explicitly set, take precedence over the log settings.
+func bindLogFlags(cmd *cobra.Command, _ []string) error {
+	for flag, setting := range logSettings {
+		if err := viper.BindPFlag(setting, cmd.Flags().Lookup(flag)); err != nil {
+			return err
+		}
 	}
 
+	return nil
+}
+
+// main executes the command chain found in the root command. It exits with the ExitCode of the error, if it has one,
+// or 1 on any other error.
+func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
+
+func exitCode(err error) int {
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return 1
+}
@@ -0,0 +1,36 @@
+package crawler
+
+import (
+	"crawler/internal/domain"
+	"crawler/internal/pkg/audit"
+
+	"github.com/spf13/viper"
+)
+
+// newAuditor configures the audit rules from the audit settings.
+func newAuditor() (audit.Auditor, error) {
+	severities := make(map[string]domain.Severity)
+
+	for rule, severity := range viper.GetStringMapString("audit.severities") {
+		severities[rule] = domain.Severity(severity)
+	}
+
+	return audit.New(audit.Config{
+		Disabled:       viper.GetStringSlice("audit.disabled"),
+		Severities:     severities,
+		MaxTitleLength: auditLimit("audit.maxTitleLength"),
+		MaxDepth:       auditLimit("audit.maxDepth"),
+		MaxRedirects:   auditLimit("audit.maxRedirects"),
+	})
+}
+
+// auditLimit reads an audit limit from the settings, or nil if it is not set so the default is used.
+func auditLimit(key string) *int {
+	if !viper.IsSet(key) {
+		return nil
+	}
+
+	limit := viper.GetInt(key)
+
+	return &limit
+}
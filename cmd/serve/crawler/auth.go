@@ -0,0 +1,111 @@
+package crawler
+
+import (
+	"crawler/internal/pkg/auth"
+	"crawler/internal/pkg/httpclient"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Errors returned when the auth settings are invalid.
+var (
+	ErrUnknownAuthType = errors.New("unknown auth type, expected basic, bearer or form")
+	ErrNoAuthHosts     = errors.New("auth has no hosts to send its credentials to")
+	ErrInvalidField    = errors.New("invalid form field, expected name=value")
+)
+
+// authSetting is one entry of the auth settings. Credentials may refer to environment variables, e.g. $PASSWORD.
+type authSetting struct {
+	Type     string
+	Hosts    []string
+	Username string
+	Password string
+	Token    string
+	LoginURL string
+	Fields   []string
+}
+
+// loadAuthSettings reads the auth settings.
+func loadAuthSettings() ([]authSetting, error) {
+	var settings []authSetting
+
+	err := viper.UnmarshalKey("auth", &settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// newAuthenticators configures an authenticator for each auth setting, scoped to its hosts. Form logins are sent by
+// doer, which must keep cookies, and built by a new Requester from newRequester.
+func newAuthenticators(
+	settings []authSetting, doer httpclient.Doer, newRequester func() httpclient.Requester,
+) ([]httpclient.Option, error) {
+	var (
+		opts []httpclient.Option
+		err  error
+	)
+
+	for _, s := range settings {
+		if len(s.Hosts) == 0 {
+			return nil, fmt.Errorf("%v: %w", s.Type, ErrNoAuthHosts)
+		}
+
+		var a httpclient.Authenticator
+
+		switch s.Type {
+		case "basic":
+			a = auth.NewBasic(os.ExpandEnv(s.Username), os.ExpandEnv(s.Password))
+		case "bearer":
+			a = auth.NewBearer(os.ExpandEnv(s.Token))
+		case "form":
+			a, err = newFormAuthenticator(s, doer, newRequester())
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%v: %w", s.Type, ErrUnknownAuthType)
+		}
+
+		opts = append(opts, httpclient.WithAuthenticator(a, s.Hosts...))
+	}
+
+	return opts, nil
+}
+
+// hasFormAuth reports whether any auth setting logs in with a form, which needs a cookie jar to keep its session.
+func hasFormAuth(settings []authSetting) bool {
+	for _, s := range settings {
+		if s.Type == "form" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newFormAuthenticator(s authSetting, doer httpclient.Doer, requester httpclient.Requester) (*auth.Form, error) {
+	loginURL, err := url.Parse(s.LoginURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(url.Values)
+
+	for _, field := range s.Fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%v: %w", field, ErrInvalidField)
+		}
+
+		fields.Add(parts[0], os.ExpandEnv(parts[1]))
+	}
+
+	return auth.NewForm(doer, requester, *loginURL, fields), nil
+}
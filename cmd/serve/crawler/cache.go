@@ -0,0 +1,73 @@
+package crawler
+
+import (
+	"crawler/internal/crawler"
+	"crawler/internal/domain"
+	"crawler/internal/pkg/report"
+	"crawler/storage/file"
+
+	"github.com/spf13/viper"
+)
+
+// loadCache reads the pages saved by the previous crawl to the cache setting, or returns nil if it is not set.
+func loadCache() (*file.File, error) {
+	path := viper.GetString("cache")
+	if path == "" {
+		return nil, nil
+	}
+
+	previous := file.New(path)
+
+	err := previous.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// updateCache compares the pages with those of the previous crawl, then saves them in its place. It does nothing if
+// there was no previous crawl.
+func updateCache(previous *file.File, pages []domain.Page, complete bool) (*domain.Changes, error) {
+	if previous == nil {
+		return nil, nil
+	}
+
+	changes := report.Compare(crawler.NewRepository(previous).All(), pages, complete)
+
+	err := saveCache(previous, pages, complete)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// saveCache saves the pages of a crawl for the next one to compare against. A crawl that was stopped early only
+// updates the pages it reached, so those it did not, including the Pending pages it queued, are still cached.
+func saveCache(previous *file.File, pages []domain.Page, complete bool) error {
+	store := previous
+	if complete {
+		store = file.New(viper.GetString("cache"))
+	}
+
+	repository := crawler.NewRepository(store)
+
+	for _, page := range pages {
+		if page.Pending {
+			continue
+		}
+
+		_, err := repository.Insert(page)
+		if err == nil {
+			continue
+		}
+
+		_, err = repository.Update(page)
+		if err != nil {
+			return err
+		}
+	}
+
+	return store.Save()
+}
@@ -0,0 +1,88 @@
+package crawler
+
+import (
+	"crawler/internal/crawler"
+	"crawler/internal/domain"
+	"crawler/storage/file"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/viper"
+)
+
+func TestSaveCache(t *testing.T) {
+	cached := []domain.Page{
+		{URL: url.URL{Host: "example.com", Path: "/"}, StatusCode: http.StatusOK, ETag: `"home"`},
+		{URL: url.URL{Host: "example.com", Path: "/queued/"}, StatusCode: http.StatusOK, ETag: `"queued"`},
+		{URL: url.URL{Host: "example.com", Path: "/not-reached/"}, StatusCode: http.StatusOK, ETag: `"not-reached"`},
+	}
+
+	tests := []struct {
+		name          string
+		givenPages    []domain.Page
+		givenComplete bool
+		expectedPages []domain.Page
+	}{
+		{
+			name: "given an interrupted crawl, expect only the pages it fetched to be updated",
+			givenPages: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/"}, StatusCode: http.StatusOK, ETag: `"home-v2"`},
+				{URL: url.URL{Host: "example.com", Path: "/queued/"}, Pending: true},
+				{URL: url.URL{Host: "example.com", Path: "/new/"}, StatusCode: http.StatusOK},
+			},
+			expectedPages: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/"}, StatusCode: http.StatusOK, ETag: `"home-v2"`},
+				{URL: url.URL{Host: "example.com", Path: "/new/"}, StatusCode: http.StatusOK},
+				{URL: url.URL{Host: "example.com", Path: "/not-reached/"}, StatusCode: http.StatusOK, ETag: `"not-reached"`},
+				{URL: url.URL{Host: "example.com", Path: "/queued/"}, StatusCode: http.StatusOK, ETag: `"queued"`},
+			},
+		},
+		{
+			name: "given a complete crawl, expect the cache to be replaced",
+			givenPages: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/"}, StatusCode: http.StatusOK, ETag: `"home-v2"`},
+			},
+			givenComplete: true,
+			expectedPages: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/"}, StatusCode: http.StatusOK, ETag: `"home-v2"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+			viper.Set("cache", path)
+			defer viper.Set("cache", "")
+
+			previous := file.New(path)
+			for _, page := range cached {
+				if _, err := crawler.NewRepository(previous).Insert(page); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := saveCache(previous, test.givenPages, test.givenComplete)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			saved, err := loadCache()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := crawler.NewRepository(saved).All()
+			sortPages := cmpopts.SortSlices(func(x, y domain.Page) bool { return x.URL.String() < y.URL.String() })
+
+			if !cmp.Equal(actual, test.expectedPages, sortPages, cmpopts.IgnoreTypes(time.Time{})) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages, sortPages, cmpopts.IgnoreTypes(time.Time{})))
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"crawler/internal/pkg/httpclient"
+	"crawler/internal/pkg/requester"
+	"crawler/internal/pkg/transport"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ErrInvalidCookie is returned if a cookie setting is not of the form name=value.
+var ErrInvalidCookie = errors.New("invalid cookie, expected name=value")
+
+// newClient configures the HTTP client from the httpTimeout, userAgent, headers, cookieJar, auth, proxy, tls and
+// transport settings, counting its responses in m and logging them to logger. The returned http.CookieJar is nil
+// unless cookies are kept.
+func newClient(m httpclient.MetricsProvider, logger log.FieldLogger) (*httpclient.Client, http.CookieJar, error) {
+	cookies, err := parseCookies(viper.GetStringSlice("cookies"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authSettings, err := loadAuthSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var jar http.CookieJar
+
+	if viper.GetBool("cookieJar") || len(cookies) > 0 || hasFormAuth(authSettings) {
+		jar, err = cookiejar.New(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	t, err := transport.New(newTransportConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doer := &http.Client{
+		Transport: t,
+		Timeout:   viper.GetDuration("httpTimeout"),
+		Jar:       jar,
+	}
+
+	opts, err := newAuthenticators(authSettings, doer, newRequester)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts = append(opts, httpclient.WithMetrics(m), httpclient.WithLogger(logger))
+
+	return httpclient.New(doer, newRequester(), opts...), jar, nil
+}
+
+// newTransportConfig reads the proxy, tls and transport settings. Unless set, as many connections are kept idle for
+// each host as URLs are fetched at once, so connections are reused rather than reopened.
+func newTransportConfig() transport.Config {
+	maxIdleConnsPerHost := viper.GetInt("transport.maxIdleConnsPerHost")
+	if !viper.IsSet("transport.maxIdleConnsPerHost") {
+		maxIdleConnsPerHost = viper.GetInt("concurrency")
+	}
+
+	return transport.Config{
+		Proxy:                 viper.GetString("proxy"),
+		CABundle:              viper.GetString("tls.caBundle"),
+		ClientCert:            viper.GetString("tls.clientCert"),
+		ClientKey:             viper.GetString("tls.clientKey"),
+		InsecureSkipVerify:    viper.GetBool("tls.insecureSkipVerify"),
+		MinTLSVersion:         viper.GetString("tls.minVersion"),
+		MaxIdleConns:          viper.GetInt("transport.maxIdleConns"),
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		MaxConnsPerHost:       viper.GetInt("transport.maxConnsPerHost"),
+		IdleConnTimeout:       viper.GetDuration("transport.idleConnTimeout"),
+		DialTimeout:           viper.GetDuration("transport.dialTimeout"),
+		TLSHandshakeTimeout:   viper.GetDuration("transport.tlsHandshakeTimeout"),
+		ResponseHeaderTimeout: viper.GetDuration("transport.responseHeaderTimeout"),
+		KeepAlive:             viper.GetDuration("transport.keepAlive"),
+		DisableKeepAlives:     viper.GetBool("transport.disableKeepAlives"),
+		DisableHTTP2:          viper.IsSet("transport.http2") && !viper.GetBool("transport.http2"),
+		DNSCacheTTL:           viper.GetDuration("transport.dnsCacheTTL"),
+	}
+}
+
+// newRequester builds requests with the userAgent and headers settings.
+func newRequester() httpclient.Requester {
+	userAgent := viper.GetString("userAgent")
+	if userAgent == "" {
+		userAgent = requester.DefaultUserAgent
+	}
+
+	headers := make(http.Header)
+	for key, value := range viper.GetStringMapString("headers") {
+		headers.Set(key, value)
+	}
+
+	return requester.New().
+		WithHeaders(headers).
+		WithHeader("User-Agent", userAgent)
+}
+
+// setCookies gives the cookie jar the cookies setting for every seed, so they are sent to the seeds' hosts.
+func setCookies(jar http.CookieJar, seeds []*url.URL) error {
+	if jar == nil {
+		return nil
+	}
+
+	cookies, err := parseCookies(viper.GetStringSlice("cookies"))
+	if err != nil {
+		return err
+	}
+
+	for _, seed := range seeds {
+		jar.SetCookies(seed, cookies)
+	}
+
+	return nil
+}
+
+func parseCookies(raw []string) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+
+	for _, r := range raw {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("%v: %w", r, ErrInvalidCookie)
+		}
+
+		cookies = append(cookies, &http.Cookie{
+			Name:  strings.TrimSpace(parts[0]),
+			Value: strings.TrimSpace(parts[1]),
+			Path:  "/",
+		})
+	}
+
+	return cookies, nil
+}
@@ -3,54 +3,226 @@ package crawler
 import (
 	"context"
 	"crawler/internal/crawler"
+	"crawler/internal/domain"
+	"crawler/internal/pkg/audit"
+	"crawler/internal/pkg/cssparser"
+	"crawler/internal/pkg/feedparser"
 	"crawler/internal/pkg/htmlparser"
-	"crawler/internal/pkg/httpclient"
+	"crawler/internal/pkg/metrics"
+	"crawler/internal/pkg/parserregistry"
 	"crawler/internal/pkg/printer"
-	"crawler/internal/pkg/requester"
+	"crawler/internal/pkg/printer/json"
+	"crawler/internal/pkg/printer/markdown"
+	"crawler/internal/pkg/progress"
+	"crawler/internal/pkg/report"
+	"crawler/internal/pkg/scope"
+	"crawler/internal/pkg/sitemap"
+	"crawler/internal/pkg/textparser"
+	"crawler/internal/pkg/threshold"
 	"crawler/internal/pkg/urlbuilder"
+	"crawler/storage/file"
 	"crawler/storage/memory"
-	"log"
-	"net/http"
+	"errors"
+	"fmt"
 	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-// New injects all the required dependencies for a crawler, crawls the given URL and returns the results.
+// ErrCrawlErrors is returned, once the report has been written, if the failOnErrors setting is true and some URLs
+// could not be crawled.
+var ErrCrawlErrors = errors.New("URLs could not be crawled")
+
+// New injects all the required dependencies for a crawler, crawls the given seed URLs and returns the results.
 func New() error {
+	logger, closeLogger, err := newLogger()
+	if err != nil {
+		return err
+	}
+	defer closeLogger()
+
+	m := metrics.New()
+	counters := metricsGroup{m}
+
+	prog := newProgress()
+	if prog != nil {
+		counters = append(counters, prog)
+	}
+
+	client, jar, err := newClient(m, logger)
+	if err != nil {
+		return err
+	}
+
+	auditor, err := newAuditor()
+	if err != nil {
+		return err
+	}
+
+	tp, err := newTracerProvider(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if tp != nil {
+		defer shutdownTracerProvider(tp, logger)
+	}
+
+	previous, err := loadCache()
+	if err != nil {
+		return err
+	}
+
+	builder := urlbuilder.New()
+
+	opts, err := newControllerOptions(client, builder, counters, logger, tp, previous)
+	if err != nil {
+		return err
+	}
+
 	controller := crawler.NewController(
 		crawler.NewRepository(
 			memory.New(),
 		),
-		httpclient.New(
-			&http.Client{
-				Timeout: viper.GetDuration("httpTimeout"),
-			},
-			requester.New(),
-		),
-		htmlparser.New(
-			urlbuilder.New(),
-		),
+		client,
+		newParser(builder),
+		opts...,
 	)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := newContext()
 	defer cancel()
 
-	baseURL := viper.GetString("baseURL")
+	stopMetrics := serveMetrics(m, logger)
+	defer stopMetrics()
+
+	seeds, err := loadSeeds(ctx, client, builder)
+	if err != nil {
+		return err
+	}
+
+	err = setCookies(jar, seeds)
+	if err != nil {
+		return err
+	}
+
+	crawlCtx, endCrawlSpan := startCrawlSpan(ctx, tp)
+	pages, crawlErrs := crawl(crawlCtx, &controller, seeds, prog, logger)
+	endCrawlSpan()
 
-	u, err := url.Parse(baseURL)
+	changes, err := updateCache(previous, pages, ctx.Err() == nil)
 	if err != nil {
 		return err
 	}
 
-	pages, err := controller.Start(ctx, u, u)
+	r := buildReport(pages, crawlErrs, changes, auditor)
+
+	err = writeReport(r)
+	if err != nil {
+		return err
+	}
+
+	return reportError(r)
+}
+
+// newControllerOptions configures the crawler.Controller from the settings. The tracer provider and the previous crawl
+// are only used if they are not nil.
+func newControllerOptions(
+	client sitemap.ClientProvider,
+	builder urlbuilder.Builder,
+	counters crawler.MetricsProvider,
+	logger log.FieldLogger,
+	tp *sdktrace.TracerProvider,
+	previous *file.File,
+) ([]crawler.Option, error) {
+	s, err := scope.New(viper.GetStringSlice("include"), viper.GetStringSlice("exclude"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []crawler.Option{
+		crawler.WithMaxDepth(viper.GetInt("maxDepth")),
+		crawler.WithConcurrency(viper.GetInt("concurrency")),
+		crawler.WithScope(s),
+		crawler.WithMetrics(counters),
+		crawler.WithLogger(logger),
+	}
+
+	if tp != nil {
+		opts = append(opts, crawler.WithTracer(tp))
+	}
+
+	if viper.IsSet("maxBodySize") {
+		opts = append(opts, crawler.WithMaxBodySize(int64(viper.GetSizeInBytes("maxBodySize"))))
+	}
+
+	if viper.GetBool("sitemaps") {
+		opts = append(opts, crawler.WithSitemaps(sitemapDiscoverer{
+			loader:  newSitemapLoader(client),
+			builder: builder,
+		}))
+	}
+
+	if previous != nil {
+		opts = append(opts, crawler.WithCache(crawler.NewRepository(previous)))
+	}
+
+	return opts, nil
+}
+
+// crawl starts the controller from the seeds, reporting its progress unless prog is nil, and returns the pages found
+// and the URLs which could not be crawled, even if ctx was done before the crawl finished.
+func crawl(
+	ctx context.Context,
+	controller *crawler.Controller,
+	seeds []*url.URL,
+	prog *progress.Progress,
+	logger log.FieldLogger,
+) ([]domain.Page, domain.CrawlErrors) {
+	if prog != nil {
+		prog.Start()
+		defer prog.Stop()
+	}
+
+	pages, crawlErrs, err := controller.Start(ctx, seeds...)
+
+	if len(crawlErrs) > 0 {
+		logger.WithField("errors", len(crawlErrs)).Warn("errors occurred while crawling, see the errors of the report")
+	}
+
 	if err != nil {
-		log.Printf("crawler errors ocuured: %v", err)
+		logger.WithField("pages", len(pages)).WithError(err).Warn("crawl stopped early, printing the pages found so far")
 	}
 
-	p := printer.New(printer.ContentType(viper.GetString("printerType")))
+	return pages, crawlErrs
+}
+
+// buildReport audits the pages and checks the thresholds against them.
+func buildReport(
+	pages []domain.Page,
+	crawlErrs domain.CrawlErrors,
+	changes *domain.Changes,
+	auditor audit.Auditor,
+) domain.Report {
+	r := report.New(pages)
+	r.Findings = auditor.Audit(pages)
+	r.Changes = changes
+	r.Errors = crawlErrs
+	r.Thresholds = threshold.Check(r, newThresholdConfig())
 
-	selectedTypeContent := p.Create(pages)
+	return r
+}
+
+// writeReport prints the report in the printerType format, to the output file or, if persist is false, to stdout.
+func writeReport(r domain.Report) error {
+	p := printer.New(printer.ContentType(viper.GetString("printerType")), viper.GetString("output"),
+		printer.WithJSON(jsonOptions()...), printer.WithMarkdown(markdownOptions()...))
+
+	selectedTypeContent := p.Create(r)
 
 	content, err := selectedTypeContent.Print()
 	if err != nil {
@@ -58,8 +230,84 @@ func New() error {
 	}
 
 	if !viper.GetBool("persist") {
+		fmt.Println(content)
+
 		return nil
 	}
 
 	return selectedTypeContent.Persist(content)
 }
+
+// reportError returns the error of the first threshold exceeded or, if the failOnErrors setting is true, of the URLs
+// which could not be crawled. It is nil if the crawl passed.
+func reportError(r domain.Report) error {
+	if err := thresholdError(r.Thresholds); err != nil {
+		return err
+	}
+
+	if viper.GetBool("failOnErrors") && len(r.Errors) > 0 {
+		return fmt.Errorf("%v errors occurred: %w", len(r.Errors), ErrCrawlErrors)
+	}
+
+	return nil
+}
+
+// jsonOptions reads the jsonReport setting.
+func jsonOptions() []json.Option {
+	if viper.GetBool("jsonReport") {
+		return []json.Option{json.WithReport()}
+	}
+
+	return nil
+}
+
+// markdownOptions reads the markdown.maxSize and markdown.slowThreshold settings, if they are set.
+func markdownOptions() []markdown.Option {
+	var opts []markdown.Option
+
+	if viper.IsSet("markdown.maxSize") {
+		opts = append(opts, markdown.WithMaxSize(int(viper.GetSizeInBytes("markdown.maxSize"))))
+	}
+
+	if viper.IsSet("markdown.slowThreshold") {
+		opts = append(opts, markdown.WithSlowThreshold(viper.GetDuration("markdown.slowThreshold")))
+	}
+
+	return opts
+}
+
+// newParser registers a parser for every content type the crawler can find links in.
+func newParser(builder urlbuilder.Builder) *parserregistry.Registry {
+	return parserregistry.New().
+		Register(parserregistry.Chain{htmlparser.New(builder), cssparser.NewHTML(builder)},
+			"text/html", "application/xhtml+xml").
+		Register(feedparser.New(builder), "application/rss+xml", "application/atom+xml", "application/xml", "text/xml").
+		Register(textparser.New(builder), "text/plain").
+		Register(cssparser.New(builder), "text/css")
+}
+
+// notifyContext is signal.NotifyContext, which tests replace to stand in for a signal.
+var notifyContext = signal.NotifyContext
+
+// newContext returns a context.Context which is cancelled on SIGINT or SIGTERM, or once maxDuration has passed.
+// As soon as it is done the default behaviour of the signals is restored, so a second signal exits immediately rather
+// than waiting for the report to be written.
+func newContext() (context.Context, context.CancelFunc) {
+	ctx, stop := notifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	cancel := context.CancelFunc(func() {})
+
+	if maxDuration := viper.GetDuration("maxDuration"); maxDuration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, maxDuration)
+	}
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
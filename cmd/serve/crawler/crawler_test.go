@@ -0,0 +1,65 @@
+package crawler
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewContext(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenMaxDuration time.Duration
+		givenSignal      bool
+	}{
+		{
+			name:        "given a signal, expect the signals to be released as soon as the context is done",
+			givenSignal: true,
+		},
+		{
+			name:             "given maxDuration passes, expect the signals to be released as soon as the context is done",
+			givenMaxDuration: 10 * time.Millisecond,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			viper.Set("maxDuration", test.givenMaxDuration)
+			defer viper.Set("maxDuration", 0)
+
+			stopped := make(chan struct{})
+			signalled, sendSignal := context.WithCancel(context.Background())
+			defer sendSignal()
+
+			notifyContext = func(_ context.Context, _ ...os.Signal) (context.Context, context.CancelFunc) {
+				return signalled, func() {
+					select {
+					case <-stopped:
+					default:
+						close(stopped)
+					}
+				}
+			}
+			defer func() { notifyContext = signal.NotifyContext }()
+
+			ctx, cancel := newContext()
+			defer cancel()
+
+			if test.givenSignal {
+				sendSignal()
+			}
+
+			<-ctx.Done()
+
+			select {
+			case <-stopped:
+			case <-time.After(time.Second):
+				t.Fatal("expected the signals to be released before the context was cancelled")
+			}
+		})
+	}
+}
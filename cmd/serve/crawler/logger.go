@@ -0,0 +1,54 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ErrUnknownLogFormat is returned if the log.format setting is neither text nor json.
+var ErrUnknownLogFormat = errors.New("unknown log format, expected text or json")
+
+// newLogger writes logs at least as severe as the log.level setting, in the log.format setting, to the log.file
+// setting or stderr. The returned func closes the file.
+func newLogger() (*log.Logger, func(), error) {
+	logger := log.New()
+
+	level := viper.GetString("log.level")
+	if level == "" {
+		level = log.InfoLevel.String()
+	}
+
+	l, err := log.ParseLevel(level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.SetLevel(l)
+
+	switch format := viper.GetString("log.format"); format {
+	case "", "text":
+		logger.SetFormatter(&log.TextFormatter{})
+	case "json":
+		logger.SetFormatter(&log.JSONFormatter{})
+	default:
+		return nil, nil, fmt.Errorf("%v: %w", format, ErrUnknownLogFormat)
+	}
+
+	path := viper.GetString("log.file")
+	if path == "" {
+		return logger, func() {}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.SetOutput(f)
+
+	return logger, func() { _ = f.Close() }, nil
+}
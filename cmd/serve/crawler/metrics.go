@@ -0,0 +1,53 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/pkg/metrics"
+	"errors"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// metricsReadHeaderTimeout is how long a scraper may take to send its request headers.
+	metricsReadHeaderTimeout = 5 * time.Second
+	// metricsShutdownTimeout is how long a scrape in progress is given to finish once the crawl is over.
+	metricsShutdownTimeout = 5 * time.Second
+)
+
+// serveMetrics serves m on /metrics at the address of the metrics setting, if it is set. The returned func stops
+// the server. Failures to serve are written to logger.
+func serveMetrics(m *metrics.Metrics, logger log.FieldLogger) func() {
+	addr := viper.GetString("metrics")
+	if addr == "" {
+		return func() {}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: metricsReadHeaderTimeout,
+	}
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.WithError(err).Error("failed to serve metrics")
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			logger.WithError(err).Error("failed to stop serving metrics")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package crawler
+
+import (
+	"crawler/internal/crawler"
+	"crawler/internal/pkg/progress"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// newProgress reports the progress of the crawl to stderr, unless the progress setting is false. A single line is
+// redrawn on a terminal, otherwise a summary is written every progressInterval.
+func newProgress() *progress.Progress {
+	if viper.IsSet("progress") && !viper.GetBool("progress") {
+		return nil
+	}
+
+	return progress.New(os.Stderr, isTerminal(os.Stderr), viper.GetDuration("progressInterval"))
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// metricsGroup counts the progress of a crawl with every MetricsProvider in it.
+type metricsGroup []crawler.MetricsProvider
+
+func (g metricsGroup) PageQueued() {
+	for _, m := range g {
+		m.PageQueued()
+	}
+}
+
+func (g metricsGroup) FetchStarted() {
+	for _, m := range g {
+		m.FetchStarted()
+	}
+}
+
+func (g metricsGroup) FetchFinished(errorClass string) {
+	for _, m := range g {
+		m.FetchFinished(errorClass)
+	}
+}
+
+func (g metricsGroup) BodyRead(bytes int) {
+	for _, m := range g {
+		m.BodyRead(bytes)
+	}
+}
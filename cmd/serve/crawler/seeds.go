@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/pkg/seeds"
+	"crawler/internal/pkg/sitemap"
+	"crawler/internal/pkg/urlbuilder"
+	"errors"
+	"net/url"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// ErrNoSeeds is returned if no seed URLs have been configured.
+var ErrNoSeeds = errors.New("no seed URLs given, set baseURL, seeds, seedsFile or seedsSitemap")
+
+// loadSeeds combines the baseURL, seeds, seedsFile and seedsSitemap settings into one list of seed URLs, cleaned the
+// same way as found links.
+func loadSeeds(ctx context.Context, client sitemap.ClientProvider, builder urlbuilder.Builder) ([]*url.URL, error) {
+	var raw []string
+
+	if baseURL := viper.GetString("baseURL"); baseURL != "" {
+		raw = append(raw, baseURL)
+	}
+
+	raw = append(raw, viper.GetStringSlice("seeds")...)
+
+	found, err := seeds.FromStrings(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if path := viper.GetString("seedsFile"); path != "" {
+		fromFile, err := seedsFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		found = append(found, fromFile...)
+	}
+
+	if location := viper.GetString("seedsSitemap"); location != "" {
+		fromSitemap, err := seedsFromSitemap(ctx, client, location)
+		if err != nil {
+			return nil, err
+		}
+
+		found = append(found, fromSitemap...)
+	}
+
+	found = cleanSeeds(builder, found)
+
+	if len(found) == 0 {
+		return nil, ErrNoSeeds
+	}
+
+	return found, nil
+}
+
+// cleanSeeds normalises every seed the same way as found links, so a seed and a link to the same page share one key
+// in the visited URLs. Seeds which are then the same are only kept once, in the order they were given.
+func cleanSeeds(builder urlbuilder.Builder, found []*url.URL) []*url.URL {
+	seen := make(map[url.URL]bool)
+
+	var cleaned []*url.URL
+
+	for _, seed := range found {
+		for _, u := range builder.Clean([]*url.URL{seed}, seed) {
+			if seen[*u] {
+				continue
+			}
+
+			seen[*u] = true
+			cleaned = append(cleaned, u)
+		}
+	}
+
+	return cleaned
+}
+
+func seedsFromFile(path string) ([]*url.URL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return seeds.Parse(f)
+}
+
+// seedsFromSitemap reads a sitemap from a URL when location is absolute, otherwise from a local file.
+func seedsFromSitemap(ctx context.Context, client sitemap.ClientProvider, location string) ([]*url.URL, error) {
+	loader := newSitemapLoader(client)
+
+	if u, err := seeds.FromString(location); err == nil {
+		return loader.Load(ctx, *u)
+	}
+
+	f, err := os.Open(location)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return loader.Read(ctx, f)
+}
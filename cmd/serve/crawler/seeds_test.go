@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+)
+
+func TestLoadSeeds(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenBaseURL  string
+		givenSeeds    []string
+		givenLink     string
+		expectedSeeds []string
+	}{
+		{
+			name:          "given a seed and a link to it which differ by a trailing slash, expect them to be the same URL",
+			givenBaseURL:  "https://example.com",
+			givenSeeds:    []string{"https://example.com/about"},
+			givenLink:     "/about/",
+			expectedSeeds: []string{"https://example.com/", "https://example.com/about/"},
+		},
+		{
+			name:          "given seeds which are the same once cleaned, expect the first to be kept",
+			givenBaseURL:  "https://example.com/docs?page=2",
+			givenSeeds:    []string{"https://example.com/docs/", "https://example.com/guide.pdf"},
+			givenLink:     "/guide.pdf",
+			expectedSeeds: []string{"https://example.com/docs/", "https://example.com/guide.pdf"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			viper.Set("baseURL", test.givenBaseURL)
+			viper.Set("seeds", test.givenSeeds)
+			defer viper.Set("baseURL", "")
+			defer viper.Set("seeds", nil)
+
+			builder := urlbuilder.New()
+
+			seeds, err := loadSeeds(context.Background(), nil, builder)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := make([]string, len(seeds))
+			for i := range seeds {
+				actual[i] = seeds[i].String()
+			}
+
+			if !cmp.Equal(actual, test.expectedSeeds) {
+				t.Fatal(cmp.Diff(actual, test.expectedSeeds))
+			}
+
+			link, err := url.Parse(test.givenLink)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := builder.Clean([]*url.URL{link}, seeds[0])
+			if *found[0] != *seeds[len(seeds)-1] {
+				t.Fatalf("expected the link %v to be the seed %v", found[0].String(), seeds[len(seeds)-1].String())
+			}
+		})
+	}
+}
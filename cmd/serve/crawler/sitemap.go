@@ -0,0 +1,33 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/pkg/sitemap"
+	"crawler/internal/pkg/urlbuilder"
+	"net/url"
+
+	"github.com/spf13/viper"
+)
+
+// sitemapDiscoverer finds the URLs in the sitemaps of a seed and cleans them the same way as found links, so a
+// page listed in a sitemap and linked from another page is only crawled once.
+type sitemapDiscoverer struct {
+	loader  sitemap.Loader
+	builder urlbuilder.Builder
+}
+
+// Discover finds the URLs in the sitemaps of the given seed.
+func (s sitemapDiscoverer) Discover(ctx context.Context, seed url.URL) ([]*url.URL, error) {
+	urls, err := s.loader.Discover(ctx, seed)
+
+	return s.builder.Clean(urls, &seed), err
+}
+
+// newSitemapLoader instantiates a sitemap.Loader which reads at most maxBodySize of each sitemap, if it is set.
+func newSitemapLoader(client sitemap.ClientProvider) sitemap.Loader {
+	if !viper.IsSet("maxBodySize") {
+		return sitemap.NewLoader(client)
+	}
+
+	return sitemap.NewLoader(client, sitemap.WithMaxSize(int64(viper.GetSizeInBytes("maxBodySize"))))
+}
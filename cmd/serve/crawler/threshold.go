@@ -0,0 +1,64 @@
+package crawler
+
+import (
+	"crawler/internal/domain"
+	"crawler/internal/pkg/threshold"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// ErrThresholdExceeded is returned, once the report has been written, if a threshold was exceeded. The crawler then
+// exits with the ExitCode of the threshold.
+var ErrThresholdExceeded = errors.New("threshold exceeded")
+
+// exitError is an error which sets the exit code of the crawler.
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode is the status the crawler exits with.
+func (e exitError) ExitCode() int {
+	return e.code
+}
+
+// newThresholdConfig reads the limit of each threshold from the thresholds settings.
+func newThresholdConfig() threshold.Config {
+	return threshold.Config{
+		MaxBrokenLinks:   thresholdLimit("thresholds.maxBrokenLinks"),
+		MaxServerErrors:  thresholdLimit("thresholds.maxServerErrors"),
+		MaxRedirectChain: thresholdLimit("thresholds.maxRedirectChain"),
+	}
+}
+
+// thresholdLimit returns the limit of setting, or threshold.Unchecked if it is not set.
+func thresholdLimit(setting string) int {
+	if !viper.IsSet(setting) {
+		return threshold.Unchecked
+	}
+
+	return viper.GetInt(setting)
+}
+
+// thresholdError returns an exitError for the first exceeded threshold, or nil if none was exceeded.
+func thresholdError(thresholds []domain.Threshold) error {
+	t, ok := threshold.Exceeded(thresholds)
+	if !ok {
+		return nil
+	}
+
+	return exitError{
+		err:  fmt.Errorf("%v: %w", t.String(), ErrThresholdExceeded),
+		code: t.ExitCode,
+	}
+}
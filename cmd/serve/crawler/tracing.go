@@ -0,0 +1,50 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/pkg/tracing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// tracerShutdownTimeout is how long the last spans are given to be exported once the crawl is over.
+const tracerShutdownTimeout = 5 * time.Second
+
+// newTracerProvider exports spans to the tracing.exporter setting, or returns nil if it is not set. The
+// tracing.endpoint and tracing.insecure settings configure the OTLP collector.
+func newTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	exporter := viper.GetString("tracing.exporter")
+	if exporter == "" {
+		return nil, nil
+	}
+
+	return tracing.New(ctx, tracing.Config{
+		Exporter: exporter,
+		Endpoint: viper.GetString("tracing.endpoint"),
+		Insecure: viper.GetBool("tracing.insecure"),
+	})
+}
+
+// startCrawlSpan starts the span every seed's span is a child of, unless tp is nil. The returned func ends it.
+func startCrawlSpan(ctx context.Context, tp *sdktrace.TracerProvider) (context.Context, func()) {
+	if tp == nil {
+		return ctx, func() {}
+	}
+
+	ctx, span := tp.Tracer("crawler/cmd/serve/crawler").Start(ctx, "crawl")
+
+	return ctx, func() { span.End() }
+}
+
+// shutdownTracerProvider exports the spans which have not been exported yet, giving up after tracerShutdownTimeout.
+func shutdownTracerProvider(tp *sdktrace.TracerProvider, logger log.FieldLogger) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+
+	if err := tp.Shutdown(ctx); err != nil {
+		logger.WithError(err).Error("failed to export spans")
+	}
+}
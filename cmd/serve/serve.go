@@ -3,8 +3,6 @@ package serve
 import (
 	"crawler/cmd/serve/crawler"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/spf13/cobra"
 )
 
@@ -13,14 +11,13 @@ func NewCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "serve",
 		Short: "serve instantiates a crawler",
-		Run:   Run,
+		RunE:  Run,
 	}
 }
 
-// Run instantiates a Crawler.
-func Run(_ *cobra.Command, _ []string) {
-	err := crawler.New()
-	if err != nil {
-		log.Printf("crawler err: %v", err)
-	}
+// Run instantiates a Crawler. Its errors are not followed by the usage, as serve takes no flags or arguments.
+func Run(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	return crawler.New()
 }
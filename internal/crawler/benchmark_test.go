@@ -0,0 +1,117 @@
+package crawler
+
+import (
+	"context"
+	"crawler/internal/pkg/htmlparser"
+	"crawler/internal/pkg/httpclient"
+	"crawler/internal/pkg/parserregistry"
+	"crawler/internal/pkg/requester"
+	"crawler/internal/pkg/transport"
+	"crawler/internal/pkg/urlbuilder"
+	"crawler/storage/memory"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// benchmarkPages is how many pages the benchmark site has. Each page links to two more, so the site is a tree.
+const benchmarkPages = 2000
+
+// benchmarkConcurrency is how many pages are fetched at once.
+const benchmarkConcurrency = 32
+
+// BenchmarkController_Start crawls a local site with the default transport, and with one whose idle connections
+// per host match the crawl's concurrency and whose DNS lookups are cached.
+func BenchmarkController_Start(b *testing.B) {
+	out := log.StandardLogger().Out
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(out)
+
+	server := httptest.NewServer(http.HandlerFunc(benchmarkPage))
+	defer server.Close()
+
+	seed, err := url.Parse(server.URL + "/page/1/")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		givenConfig transport.Config
+	}{
+		{
+			name: "default transport",
+		},
+		{
+			name: "tuned transport",
+			givenConfig: transport.Config{
+				MaxIdleConnsPerHost: benchmarkConcurrency,
+				DNSCacheTTL:         time.Minute,
+			},
+		},
+	}
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			t, err := transport.New(test.givenConfig)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer t.CloseIdleConnections()
+
+			client := httpclient.New(&http.Client{Transport: t}, requester.New())
+			parser := parserregistry.New().Register(htmlparser.New(urlbuilder.New()), "text/html")
+
+			b.ResetTimer()
+			start := time.Now()
+
+			for i := 0; i < b.N; i++ {
+				c := NewController(NewRepository(memory.New()), client, parser, WithConcurrency(benchmarkConcurrency))
+
+				pages, errs, err := c.Start(context.Background(), seed)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if len(errs) > 0 {
+					b.Fatal(errs)
+				}
+
+				if len(pages) != benchmarkPages {
+					b.Fatalf("expected %v pages, got %v", benchmarkPages, len(pages))
+				}
+			}
+
+			b.ReportMetric(float64(b.N*benchmarkPages)/time.Since(start).Seconds(), "pages/s")
+		})
+	}
+}
+
+// benchmarkPage serves /page/n/, which links to pages 2n and 2n+1 if they are part of the site.
+func benchmarkPage(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/page/"), "/"))
+	if err != nil || n < 1 || n > benchmarkPages {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	var links strings.Builder
+
+	for _, child := range []int{2 * n, 2*n + 1} {
+		if child <= benchmarkPages {
+			fmt.Fprintf(&links, `<a href="/page/%v/">Page %v</a>`, child, child)
+		}
+	}
+
+	fmt.Fprintf(w, "<html><head><title>Page %v</title></head><body><main><p>Page %v of the benchmark site.</p>%v</main></body></html>",
+		n, n, links.String())
+}
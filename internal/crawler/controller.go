@@ -1,8 +1,14 @@
 package crawler
 
 import (
+	"bytes"
 	"context"
 	"crawler/internal/domain"
+	"crawler/internal/pkg/fingerprint"
+	"crawler/internal/pkg/httpclient"
+	"crawler/internal/pkg/parserregistry"
+	"crawler/internal/pkg/sitemap"
+	"crawler/internal/pkg/transport"
 	"crawler/storage/memory"
 	"errors"
 	"fmt"
@@ -12,36 +18,140 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrFailedToReadBody is returned if the body of a http.Response cannot be read.
+var (
+	ErrFailedToReadBody = errors.New("failed to read body")
+)
+
+// DefaultMaxBodySize is the most of a http.Response body that will be read, unless WithMaxBodySize is given.
+const DefaultMaxBodySize = 10 << 20
+
+// tracerName identifies the spans of a Controller.
+const tracerName = "crawler/internal/crawler"
+
+// maxDrainSize is the most of an unread body that will be discarded to allow its connection to be reused.
+const maxDrainSize = 64 << 10
+
 // Controller fetches all available URLs and returns them.
 type Controller struct {
-	Repository RepositoryProvider
-	Client     ClientProvider
-	Parser     Parser
-	err        chan error
-	timeout    *time.Timer
-	page       chan domain.Page
-	foundURLS  chan *url.URL
+	Repository  RepositoryProvider
+	Client      ClientProvider
+	Parser      Parser
+	Scope       ScopeProvider
+	Sitemaps    SitemapProvider
+	Cache       CacheProvider
+	Metrics     MetricsProvider
+	Logger      log.FieldLogger
+	Tracer      trace.Tracer
+	maxDepth    int
+	maxBodySize int64
+	slots       chan struct{}
+	results     chan result
+}
+
+// Option configures optional behaviour of a Controller.
+type Option func(c *Controller)
+
+// WithMaxDepth limits how many links away from a seed URL will be crawled. Zero means unlimited.
+func WithMaxDepth(depth int) Option {
+	return func(c *Controller) {
+		c.maxDepth = depth
+	}
+}
+
+// WithMaxBodySize limits how many bytes of a http.Response body will be read and parsed. Zero means unlimited.
+func WithMaxBodySize(size int64) Option {
+	return func(c *Controller) {
+		c.maxBodySize = size
+	}
+}
+
+// WithConcurrency limits how many URLs can be fetched at the same time. Zero means unlimited.
+func WithConcurrency(concurrency int) Option {
+	return func(c *Controller) {
+		if concurrency <= 0 {
+			c.slots = nil
+			return
+		}
+
+		c.slots = make(chan struct{}, concurrency)
+	}
+}
+
+// WithScope restricts which found URLs will be crawled.
+func WithScope(scope ScopeProvider) Option {
+	return func(c *Controller) {
+		c.Scope = scope
+	}
+}
+
+// WithSitemaps crawls the URLs listed in the sitemaps of each seed, as well as those found through links.
+func WithSitemaps(sitemaps SitemapProvider) Option {
+	return func(c *Controller) {
+		c.Sitemaps = sitemaps
+	}
+}
+
+// WithCache makes a conditional request for every URL in the cache which has an ETag or Last-Modified, reusing its
+// cached results if it has not changed. The Client must be a ConditionalClientProvider for this to take effect.
+func WithCache(cache CacheProvider) Option {
+	return func(c *Controller) {
+		c.Cache = cache
+	}
+}
+
+// WithMetrics counts the pages queued, fetched and in flight, their errors and the bytes read with metrics.
+func WithMetrics(metrics MetricsProvider) Option {
+	return func(c *Controller) {
+		c.Metrics = metrics
+	}
+}
+
+// WithLogger writes a line for every page crawled to logger, with its url, referrer, depth, status and duration.
+func WithLogger(logger log.FieldLogger) Option {
+	return func(c *Controller) {
+		c.Logger = logger
+	}
+}
+
+// WithTracer traces the crawl of every page, and its fetch, parse and repository calls, with spans from provider.
+// The span of each page is a child of the span of the page it was linked from.
+func WithTracer(provider trace.TracerProvider) Option {
+	return func(c *Controller) {
+		c.Tracer = provider.Tracer(tracerName)
+	}
 }
 
 // NewController instantiates a Controller.
-func NewController(repo RepositoryProvider, client ClientProvider, parser Parser) Controller {
-	return Controller{
-		Repository: repo,
-		Client:     client,
-		Parser:     parser,
-		err:        make(chan error),
-		page:       make(chan domain.Page),
-		foundURLS:  make(chan *url.URL),
-		timeout:    time.NewTimer(time.Second * 10),
+func NewController(repo RepositoryProvider, client ClientProvider, parser Parser, opts ...Option) Controller {
+	c := Controller{
+		Repository:  repo,
+		Client:      client,
+		Parser:      parser,
+		Metrics:     noMetrics{},
+		Logger:      log.StandardLogger(),
+		Tracer:      trace.NewNoopTracerProvider().Tracer(tracerName),
+		maxBodySize: DefaultMaxBodySize,
+		results:     make(chan result),
 	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
 }
 
 // RepositoryProvider gives access to a storage interface.
 type RepositoryProvider interface {
 	Get(url url.URL) (domain.Page, error)
 	Insert(page domain.Page) (domain.Page, error)
+	Update(page domain.Page) (domain.Page, error)
 }
 
 // ClientProvider gives the ability to perform HTTP Requests.
@@ -49,86 +159,654 @@ type ClientProvider interface {
 	Fetch(ctx context.Context, url url.URL) (*http.Response, error)
 }
 
-// Parser will find all the desired URLs for a given http.Response.
+// ConditionalClientProvider gives the ability to perform HTTP Requests which only return a body if it has changed.
+type ConditionalClientProvider interface {
+	ClientProvider
+	FetchIfModified(ctx context.Context, url url.URL, etag, lastModified string) (*http.Response, error)
+}
+
+// CacheProvider gives access to the domain.Page's of a previous crawl.
+type CacheProvider interface {
+	Get(url url.URL) (domain.Page, error)
+}
+
+// Parser will find all the desired URLs, and any metadata, for a given http.Response body of the given content type.
+// The span in ctx is the parent of any span started while parsing.
 type Parser interface {
-	FetchLinks(html io.Reader, baseURL *url.URL) ([]*url.URL, error)
+	Parse(ctx context.Context, body io.Reader, contentType string, baseURL *url.URL) (domain.Document, error)
+	Supports(contentType string) bool
+}
+
+// ScopeProvider decides whether a found URL should be crawled.
+type ScopeProvider interface {
+	Allows(u url.URL) bool
+}
+
+// SitemapProvider finds the URLs listed in the sitemaps of a seed.
+type SitemapProvider interface {
+	Discover(ctx context.Context, seed url.URL) ([]*url.URL, error)
+}
+
+// MetricsProvider counts the progress of a crawl.
+type MetricsProvider interface {
+	PageQueued()
+	FetchStarted()
+	FetchFinished(errorClass string)
+	BodyRead(bytes int)
+}
+
+// noMetrics counts nothing, for a Controller without metrics.
+type noMetrics struct{}
+
+func (noMetrics) PageQueued()            {}
+func (noMetrics) FetchStarted()          {}
+func (noMetrics) FetchFinished(_ string) {}
+func (noMetrics) BodyRead(_ int)         {}
+
+// task is a single URL waiting to be crawled.
+type task struct {
+	url      *url.URL
+	referrer url.URL
+	seed     *url.URL
+	source   domain.Source
+	depth    int
+	page     domain.Page
+	index    int
+	parent   trace.SpanContext
+	span     trace.SpanContext
 }
 
-// Start initiates the crawler and returns the Pages found and any errors that happened.
-func (c *Controller) Start(ctx context.Context, baseURL, targetURL *url.URL) ([]domain.Page, error) {
+// result is the outcome of crawling a task, or of discovering the sitemap URLs of a seed.
+type result struct {
+	task   task
+	page   domain.Page
+	links  []*url.URL
+	source domain.Source
+	err    error
+}
+
+// Start initiates the crawler from every seed and returns the Pages found and a domain.CrawlError for every URL which
+// could not be crawled. All seeds share the same visited URLs, so a page reachable from several seeds is only crawled
+// once. If ctx is done before the crawl finishes, the Pages and errors found so far are returned along with ctx's
+// error, and the Pages which were queued but not fetched are Pending.
+func (c *Controller) Start(ctx context.Context, seeds ...*url.URL) ([]domain.Page, domain.CrawlErrors, error) {
 	var pageResults []domain.Page
-	var errs error
+	var errs domain.CrawlErrors
+
+	pending := 0
+	root := trace.SpanContextFromContext(ctx)
+	linked := make(map[url.URL]bool)
+	discovered := make(map[string]bool)
+
+	enqueue := func(t task) {
+		page, ok, err := c.record(ctx, t)
+		if err != nil {
+			errs = append(errs, *err)
+		}
+
+		if !ok {
+			return
+		}
 
-	c.crawl(ctx, baseURL, targetURL)
+		t.page = page
+		t.index = len(pageResults)
+
+		// The page stays pending unless its result is received before the crawl is stopped.
+		queued := page
+		queued.Pending = true
+		pageResults = append(pageResults, queued)
+
+		pending++
+		c.Metrics.PageQueued()
+
+		go c.crawl(ctx, t)
+	}
+
+	for _, seed := range seeds {
+		t := task{
+			url:    seed,
+			seed:   seed,
+			source: domain.SourceSeed,
+			parent: root,
+		}
+
+		if c.Sitemaps != nil && !discovered[seed.Host] {
+			discovered[seed.Host] = true
+
+			pending++
+			go c.discover(ctx, t)
+		}
+
+		enqueue(t)
+	}
+
+	for pending > 0 {
+		var res result
 
-	for {
 		select {
-		case page := <-c.page:
-			pageResults = append(pageResults, page)
-			log.Infof("received page from channel: %v", page.URL)
-			c.timeout.Reset(time.Second * 10)
-		case foundURL := <-c.foundURLS:
-			log.Infof("received URL from channel: %v", foundURL.String())
-			go c.crawl(ctx, baseURL, foundURL)
-		case err := <-c.err:
-			errs = fmt.Errorf("%v: %w", errs, err)
-			log.Infof("received err from channel: %v", err)
-		case <-c.timeout.C:
-			return pageResults, errs
+		case res = <-c.results:
+		case <-ctx.Done():
+			c.Logger.WithField("pending", pending).WithError(ctx.Err()).Info("crawl stopped early")
+
+			return markLinked(pageResults, linked), errs, ctx.Err()
+		}
+
+		pending--
+
+		var crawlErr *domain.CrawlError
+		if errors.As(res.err, &crawlErr) {
+			errs = append(errs, *crawlErr)
+		}
+
+		if res.source == domain.SourceLink {
+			pageResults[res.task.index] = res.page
+
+			if err := c.update(ctx, res.task, res.page); err != nil {
+				errs = append(errs, *err)
+			}
+		}
+
+		for _, link := range res.links {
+			if res.source == domain.SourceLink {
+				linked[*link] = true
+			}
+
+			next := res.next(link)
+
+			if !c.inScope(next) {
+				continue
+			}
+
+			enqueue(next)
+		}
+	}
+
+	return markLinked(pageResults, linked), errs, nil
+}
+
+// markLinked flags every page which was found through a link on another page.
+func markLinked(pages []domain.Page, linked map[url.URL]bool) []domain.Page {
+	for i := range pages {
+		pages[i].Linked = linked[pages[i].URL]
+	}
+
+	return pages
+}
+
+// next creates the task for a URL found by this result. URLs found in a sitemap are entry points in their own
+// right, so they keep the depth of their seed and have no referrer.
+func (r result) next(link *url.URL) task {
+	if r.source == domain.SourceSitemap {
+		return task{
+			url:    link,
+			seed:   r.task.seed,
+			source: r.source,
+			depth:  r.task.depth,
+			parent: r.task.span,
 		}
 	}
+
+	return task{
+		url:      link,
+		referrer: *r.task.url,
+		seed:     r.task.seed,
+		source:   r.source,
+		depth:    r.task.depth + 1,
+		parent:   r.task.span,
+	}
+}
+
+// inScope reports whether a found URL is within the configured depth and scope rules.
+func (c *Controller) inScope(t task) bool {
+	if c.maxDepth > 0 && t.depth > c.maxDepth {
+		return false
+	}
+
+	return c.Scope == nil || c.Scope.Allows(*t.url)
+}
+
+// record stores the task as a domain.Page, returning false if it has already been seen or could not be stored.
+func (c *Controller) record(ctx context.Context, t task) (domain.Page, bool, *domain.CrawlError) {
+	_, span := c.startSpan(ctx, t.parent, "Repository.Get", *t.url)
+	_, err := c.Repository.Get(*t.url)
+	span.End()
+
+	if !errors.Is(err, memory.ErrInvalidKey) {
+		return domain.Page{}, false, nil
+	}
+
+	page := domain.Page{
+		Referrer:  t.referrer,
+		URL:       *t.url,
+		Seed:      *t.seed,
+		Source:    t.source,
+		Depth:     t.depth,
+		CrawledAt: time.Now().UTC(),
+	}
+
+	_, span = c.startSpan(ctx, t.parent, "Repository.Insert", *t.url)
+	_, err = c.Repository.Insert(page)
+	endSpan(span, err)
+
+	if err != nil {
+		c.Logger.WithField("url", t.url.String()).WithError(err).Warn("failed to store page")
+
+		return domain.Page{}, false, newCrawlError(t, domain.PhaseStore, 0, err)
+	}
+
+	return page, true, nil
 }
 
-func (c *Controller) crawl(ctx context.Context, baseURL, targetURL *url.URL) {
-	res, err := c.Client.Fetch(ctx, *targetURL)
+// update stores the result of fetching a domain.Page.
+func (c *Controller) update(ctx context.Context, t task, page domain.Page) *domain.CrawlError {
+	_, span := c.startSpan(ctx, t.span, "Repository.Update", page.URL)
+	_, err := c.Repository.Update(page)
+	endSpan(span, err)
+
 	if err != nil {
-		log.Errorf("fetch error for %v", targetURL)
+		c.Logger.WithField("url", page.URL.String()).WithError(err).Warn("failed to update page")
 
-		go func() {
-			c.err <- fmt.Errorf("%v: %w", targetURL, err)
-		}()
+		return newCrawlError(t, domain.PhaseStore, page.StatusCode, err)
+	}
+
+	return nil
+}
+
+// newCrawlError describes err as a domain.CrawlError of crawling the task's URL in phase.
+func newCrawlError(t task, phase domain.Phase, status int, err error) *domain.CrawlError {
+	return &domain.CrawlError{
+		URL:      *t.url,
+		Referrer: t.referrer,
+		Phase:    phase,
+		Status:   status,
+		Err:      err,
+	}
+}
+
+func (c *Controller) crawl(ctx context.Context, t task) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			defer func() { <-c.slots }()
+		case <-ctx.Done():
+			return
+		}
+	}
 
+	if ctx.Err() != nil {
 		return
 	}
 
-	links, err := c.Parser.FetchLinks(res.Body, baseURL)
+	start := time.Now()
+
+	ctx, span := c.startSpan(ctx, t.parent, "crawl page", *t.url, attribute.Int("depth", t.depth))
+	t.span = span.SpanContext()
+
+	c.Metrics.FetchStarted()
+	page, links, err := c.fetch(ctx, t)
+	c.Metrics.FetchFinished(errorClass(err))
+	c.logFetch(page, time.Since(start), err)
+
+	span.SetAttributes(attribute.Int("status", page.StatusCode))
+	endSpan(span, err)
+
+	c.send(ctx, result{
+		task:   t,
+		page:   page,
+		links:  links,
+		source: domain.SourceLink,
+		err:    err,
+	})
+}
+
+// fetch requests the task's URL, records the response on its domain.Page and returns the links found in the body.
+// The body is only parsed if the Parser supports its content type, and only up to the maximum body size. If the URL
+// has not changed since it was cached, the cached page and links are returned instead. The fetch is timed until the
+// body has been read, or until the response headers if it is not.
+func (c *Controller) fetch(ctx context.Context, t task) (domain.Page, []*url.URL, error) {
+	page := t.page
+	cached, isCached := c.cached(*t.url)
+
+	_, span := c.startSpan(ctx, t.span, "Client.Fetch", *t.url)
+	start := time.Now()
+	res, err := c.request(ctx, *t.url, cached, isCached)
+	page.FetchDuration = time.Since(start)
+	endSpan(span, err)
+
 	if err != nil {
-		log.Errorf("create links for %v", targetURL)
+		return page, nil, requestError(t, &page, err)
+	}
+	defer closeBody(res.Body)
 
-		go func() {
-			c.err <- err
-		}()
+	page.Redirects = httpclient.Redirects(res)
+	page.Certificate = certificate(res)
 
-		return
+	if isCached && res.StatusCode == http.StatusNotModified {
+		return unchanged(page, cached), toPointers(cached.Links), nil
 	}
 
-	for _, link := range links {
-		_, err = c.Repository.Get(*link)
-		if !errors.Is(err, memory.ErrInvalidKey) {
-			continue
-		}
+	page.StatusCode = res.StatusCode
+	page.ContentType = res.Header.Get("Content-Type")
+	page.ETag = res.Header.Get("ETag")
+	page.LastModified = res.Header.Get("Last-Modified")
 
-		page := domain.Page{
-			Referrer:  *targetURL,
-			URL:       *link,
-			CrawledAt: time.Now().UTC(),
-		}
+	if final := finalURL(t.url, page.Redirects); final != t.url && !c.onSite(t, *final) {
+		page.SkipReason = fmt.Sprintf("redirected to %v, which is not crawled", final.String())
 
-		_, err := c.Repository.Insert(page)
-		if err != nil {
-			log.Infof("repo for %v", targetURL)
-			continue
-		}
+		return page, nil, nil
+	}
+
+	if !c.Parser.Supports(page.ContentType) {
+		page.SkipReason = fmt.Sprintf("content type %v is not supported", page.ContentType)
+
+		return page, nil, nil
+	}
+
+	body, truncated, err := c.readBody(res.Body)
+	page.FetchDuration = time.Since(start)
+
+	if err != nil {
+		return page, nil, newCrawlError(t, domain.PhaseFetch, page.StatusCode, fmt.Errorf("%v: %w", err, ErrFailedToReadBody))
+	}
 
-		link := link
-		go func() {
-			c.foundURLS <- link
-			log.Infof("a url has been sent to the channel: %v", link.String())
+	c.Metrics.BodyRead(len(body))
 
-			c.page <- page
-			log.Infof("a page has been sent to the channel: %v", page.URL)
-		}()
+	if truncated {
+		page.Truncated = true
+		page.SkipReason = fmt.Sprintf("body is larger than %v bytes, only the start was parsed", c.maxBodySize)
+	}
+
+	parseCtx, span := c.startSpan(ctx, t.span, "Parser.Parse", *t.url, attribute.String("content_type", page.ContentType))
+	doc, err := c.Parser.Parse(parseCtx, bytes.NewReader(body), page.ContentType, finalURL(t.url, page.Redirects))
+	span.SetAttributes(attribute.Int("links", len(doc.Links)))
+	endSpan(span, err)
+
+	if errors.Is(err, parserregistry.ErrUnsupportedContentType) {
+		page.SkipReason = err.Error()
+
+		return page, nil, nil
+	}
+
+	if err != nil {
+		return page, nil, newCrawlError(t, domain.PhaseParse, page.StatusCode, err)
 	}
 
-	log.Infof("all URLs have been crawled for %v", targetURL)
+	page.Metadata = doc.Metadata
+	page.Outlinks = doc.Outlinks
+	page.Links = toValues(doc.Links)
+
+	if doc.Text != "" {
+		page.ContentHash = fingerprint.Hash(doc.Text)
+		page.SimHash = fingerprint.SimHash(doc.Text)
+	}
+
+	return page, doc.Links, nil
+}
+
+// requestError records the status and redirects of a failed request on the page, and returns the error as a
+// CrawlError of the phase it failed in.
+func requestError(t task, page *domain.Page, err error) error {
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) {
+		page.StatusCode = statusErr.StatusCode
+		page.Redirects = statusErr.Redirects
+	}
+
+	phase := domain.PhaseFetch
+	if errors.Is(err, httpclient.ErrFailedToBuildRequest) {
+		phase = domain.PhaseBuild
+	}
+
+	return newCrawlError(t, phase, page.StatusCode, err)
+}
+
+// logFetch writes a line for a fetched page, with the error if it failed.
+func (c *Controller) logFetch(page domain.Page, duration time.Duration, err error) {
+	entry := c.Logger.WithFields(log.Fields{
+		"url":      page.URL.String(),
+		"referrer": page.Referrer.String(),
+		"depth":    page.Depth,
+		"status":   page.StatusCode,
+		"duration": duration.Seconds(),
+	})
+
+	switch {
+	case err != nil:
+		entry.WithError(err).Debug("failed to crawl page")
+	case page.Unchanged:
+		entry.Debug("page unchanged since the last crawl")
+	default:
+		entry.Debug("crawled page")
+	}
+}
+
+// cached returns the domain.Page for a URL from the cache, if there is one.
+func (c *Controller) cached(u url.URL) (domain.Page, bool) {
+	if c.Cache == nil {
+		return domain.Page{}, false
+	}
+
+	page, err := c.Cache.Get(u)
+
+	return page, err == nil
+}
+
+// request fetches a URL, conditionally if it has been cached with an ETag or Last-Modified.
+func (c *Controller) request(ctx context.Context, u url.URL, cached domain.Page, isCached bool) (*http.Response, error) {
+	client, ok := c.Client.(ConditionalClientProvider)
+	if !ok || !isCached || (cached.ETag == "" && cached.LastModified == "") {
+		return c.Client.Fetch(ctx, u)
+	}
+
+	return client.FetchIfModified(ctx, u, cached.ETag, cached.LastModified)
+}
+
+// certificate describes the TLS certificate of the host which sent the response, or is nil if it was not sent over
+// TLS.
+func certificate(res *http.Response) *domain.Certificate {
+	if res.TLS == nil || len(res.TLS.PeerCertificates) == 0 || res.Request == nil {
+		return nil
+	}
+
+	leaf := res.TLS.PeerCertificates[0]
+
+	return &domain.Certificate{
+		Host:       res.Request.URL.Host,
+		Subject:    leaf.Subject.String(),
+		Issuer:     leaf.Issuer.String(),
+		DNSNames:   leaf.DNSNames,
+		NotBefore:  leaf.NotBefore,
+		NotAfter:   leaf.NotAfter,
+		TLSVersion: transport.VersionName(res.TLS.Version),
+	}
+}
+
+// unchanged copies the results of the cached domain.Page onto the page being crawled.
+func unchanged(page, cached domain.Page) domain.Page {
+	page.StatusCode = cached.StatusCode
+	page.ContentType = cached.ContentType
+	page.ETag = cached.ETag
+	page.LastModified = cached.LastModified
+	page.Unchanged = true
+	page.Truncated = cached.Truncated
+	page.SkipReason = cached.SkipReason
+	page.Metadata = cached.Metadata
+	page.ContentHash = cached.ContentHash
+	page.SimHash = cached.SimHash
+	page.Outlinks = cached.Outlinks
+	page.Links = cached.Links
+
+	return page
+}
+
+func toValues(urls []*url.URL) []url.URL {
+	var values []url.URL
+
+	for _, u := range urls {
+		values = append(values, *u)
+	}
+
+	return values
+}
+
+func toPointers(urls []url.URL) []*url.URL {
+	var pointers []*url.URL
+
+	for i := range urls {
+		pointers = append(pointers, &urls[i])
+	}
+
+	return pointers
+}
+
+// onSite reports whether a response from u, which the task's URL was redirected to, should be parsed. It must be on the
+// host of the task's seed and within scope, otherwise the links of another site would be crawled.
+func (c *Controller) onSite(t task, u url.URL) bool {
+	return u.Hostname() == t.seed.Hostname() && (c.Scope == nil || c.Scope.Allows(u))
+}
+
+// finalURL is the URL a response came from, which links within it are relative to.
+func finalURL(u *url.URL, redirects []url.URL) *url.URL {
+	if len(redirects) == 0 {
+		return u
+	}
+
+	return &redirects[len(redirects)-1]
+}
+
+// readBody reads up to the maximum body size, reporting whether there was more to read.
+func (c *Controller) readBody(body io.Reader) ([]byte, bool, error) {
+	if c.maxBodySize <= 0 {
+		b, err := io.ReadAll(body)
+
+		return b, false, err
+	}
+
+	b, err := io.ReadAll(io.LimitReader(body, c.maxBodySize+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(b)) > c.maxBodySize {
+		return b[:c.maxBodySize], true, nil
+	}
+
+	return b, false, nil
+}
+
+// Classes of the errors counted by a MetricsProvider.
+const (
+	ErrorClassStatus   = "status"
+	ErrorClassTimeout  = "timeout"
+	ErrorClassNetwork  = "network"
+	ErrorClassAuth     = "auth"
+	ErrorClassRequest  = "request"
+	ErrorClassRead     = "read"
+	ErrorClassParse    = "parse"
+	ErrorClassCanceled = "canceled"
+)
+
+// errorClass groups an error returned by fetch by what went wrong, or is empty if there was no error.
+func errorClass(err error) string {
+	var statusErr *httpclient.StatusError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &statusErr):
+		return ErrorClassStatus
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, httpclient.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, httpclient.ErrFailedRequest):
+		return ErrorClassNetwork
+	case errors.Is(err, httpclient.ErrFailedToAuthenticate):
+		return ErrorClassAuth
+	case errors.Is(err, httpclient.ErrFailedToBuildRequest):
+		return ErrorClassRequest
+	case errors.Is(err, ErrFailedToReadBody):
+		return ErrorClassRead
+	default:
+		return ErrorClassParse
+	}
+}
+
+// closeBody discards what is left of the body, up to maxDrainSize, so the connection can be reused, then closes it.
+func closeBody(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxDrainSize))
+	_ = body.Close()
+}
+
+// discover finds the URLs listed in the sitemaps of the task's seed.
+func (c *Controller) discover(ctx context.Context, t task) {
+	ctx, span := c.startSpan(ctx, t.parent, "Sitemaps.Discover", *t.seed)
+	t.span = span.SpanContext()
+
+	links, err := c.Sitemaps.Discover(ctx, *t.seed)
+	if err != nil {
+		err = sitemapError(t, err)
+	}
+
+	endSpan(span, err)
+
+	c.send(ctx, result{
+		task:   t,
+		links:  links,
+		source: domain.SourceSitemap,
+		err:    err,
+	})
+}
+
+// sitemapError returns the error of discovering the sitemaps of the task's seed as a CrawlError of the sitemap which
+// failed, linked from the seed, so the seed itself is not reported as broken.
+func sitemapError(t task, err error) *domain.CrawlError {
+	crawlErr := &domain.CrawlError{
+		URL:      *t.seed,
+		Referrer: *t.seed,
+		Phase:    domain.PhaseSitemap,
+		Err:      err,
+	}
+
+	var loadErr *sitemap.LoadError
+	if errors.As(err, &loadErr) {
+		crawlErr.URL = loadErr.URL
+		crawlErr.Err = loadErr.Err
+	}
+
+	var statusErr *httpclient.StatusError
+	if errors.As(err, &statusErr) {
+		crawlErr.Status = statusErr.StatusCode
+	}
+
+	return crawlErr
+}
+
+// startSpan starts a span for u which is a child of parent, rather than of any span in ctx.
+func (c *Controller) startSpan(
+	ctx context.Context, parent trace.SpanContext, name string, u url.URL, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return c.Tracer.Start(
+		trace.ContextWithSpanContext(ctx, parent),
+		name,
+		trace.WithAttributes(append(attrs, attribute.String("url", u.String()))...),
+	)
+}
+
+// endSpan ends the span, recording err if it is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// send hands the result back to Start, unless Start has already returned because ctx is done.
+func (c *Controller) send(ctx context.Context, r result) {
+	select {
+	case c.results <- r:
+	case <-ctx.Done():
+	}
 }
@@ -3,18 +3,30 @@ package crawler
 import (
 	"context"
 	"crawler/internal/domain"
+	"crawler/internal/pkg/fingerprint"
 	"crawler/internal/pkg/htmlparser"
 	"crawler/internal/pkg/httpclient"
+	"crawler/internal/pkg/sitemap"
+	"crawler/internal/pkg/tracing"
 	"crawler/storage/memory"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -59,6 +71,20 @@ func TestController_Start_Success(t *testing.T) {
 				},
 			},
 			expectedPages: []domain.Page{
+				{
+					URL: url.URL{
+						Host: "example.com",
+					},
+					Seed: url.URL{
+						Host: "example.com",
+					},
+					Source: domain.SourceSeed,
+					Links: []url.URL{
+						{Host: "example.com", Path: "/1/"},
+						{Host: "example.com", Path: "/2/"},
+						{Host: "example.com", Path: "/3/"},
+					},
+				},
 				{
 					URL: url.URL{
 						Host: "example.com",
@@ -67,6 +93,12 @@ func TestController_Start_Success(t *testing.T) {
 					Referrer: url.URL{
 						Host: "example.com",
 					},
+					Seed: url.URL{
+						Host: "example.com",
+					},
+					Source: domain.SourceLink,
+					Linked: true,
+					Depth:  1,
 				},
 				{
 					URL: url.URL{
@@ -76,6 +108,12 @@ func TestController_Start_Success(t *testing.T) {
 					Referrer: url.URL{
 						Host: "example.com",
 					},
+					Seed: url.URL{
+						Host: "example.com",
+					},
+					Source: domain.SourceLink,
+					Linked: true,
+					Depth:  1,
 				},
 				{
 					URL: url.URL{
@@ -85,6 +123,12 @@ func TestController_Start_Success(t *testing.T) {
 					Referrer: url.URL{
 						Host: "example.com",
 					},
+					Seed: url.URL{
+						Host: "example.com",
+					},
+					Source: domain.SourceLink,
+					Linked: true,
+					Depth:  1,
 				},
 			},
 		},
@@ -93,17 +137,903 @@ func TestController_Start_Success(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			c := NewController(test.givenRepo, test.givenClient, test.givenParser)
 
-			actual, err := c.Start(context.Background(), test.givenBaseURL, test.givenBaseURL)
-			if err == nil {
+			actual, errs, err := c.Start(context.Background(), test.givenBaseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(errs) == 0 {
+				t.Fatal("expected errors, got none")
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].URL.Path < actual[j].URL.Path
+			})
+
+			if !cmp.Equal(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))))
+			}
+		})
+	}
+}
+
+func TestController_Start_WithOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenOptions  []Option
+		givenParser   Parser
+		expectedPages []domain.Page
+	}{
+		{
+			name:         "given a max depth of 2, expect links deeper than 2 to be ignored",
+			givenOptions: []Option{WithMaxDepth(2), WithConcurrency(1)},
+			givenParser:  &mockChainParser{},
+			expectedPages: []domain.Page{
+				{
+					URL:    url.URL{Host: "example.com"},
+					Seed:   url.URL{Host: "example.com"},
+					Source: domain.SourceSeed,
+					Links:  []url.URL{{Host: "example.com", Path: "/1/"}},
+				},
+				{
+					URL:      url.URL{Host: "example.com", Path: "/1/"},
+					Referrer: url.URL{Host: "example.com"},
+					Seed:     url.URL{Host: "example.com"},
+					Source:   domain.SourceLink,
+					Linked:   true,
+					Depth:    1,
+					Links:    []url.URL{{Host: "example.com", Path: "/2/"}},
+				},
+				{
+					URL:      url.URL{Host: "example.com", Path: "/2/"},
+					Referrer: url.URL{Host: "example.com", Path: "/1/"},
+					Seed:     url.URL{Host: "example.com"},
+					Source:   domain.SourceLink,
+					Linked:   true,
+					Depth:    2,
+					Links:    []url.URL{{Host: "example.com", Path: "/3/"}},
+				},
+			},
+		},
+		{
+			name:         "given a scope excluding a path, expect that link to be ignored",
+			givenOptions: []Option{WithScope(mockScope{GivenDisallowedPath: "/2/"})},
+			givenParser: mockParser{
+				GivenURLs: []*url.URL{
+					{Host: "example.com", Path: "/1/"},
+					{Host: "example.com", Path: "/2/"},
+				},
+			},
+			expectedPages: []domain.Page{
+				{
+					URL:    url.URL{Host: "example.com"},
+					Seed:   url.URL{Host: "example.com"},
+					Source: domain.SourceSeed,
+					Links:  []url.URL{{Host: "example.com", Path: "/1/"}, {Host: "example.com", Path: "/2/"}},
+				},
+				{
+					URL:      url.URL{Host: "example.com", Path: "/1/"},
+					Referrer: url.URL{Host: "example.com"},
+					Seed:     url.URL{Host: "example.com"},
+					Source:   domain.SourceLink,
+					Linked:   true,
+					Depth:    1,
+					Links:    []url.URL{{Host: "example.com", Path: "/1/"}, {Host: "example.com", Path: "/2/"}},
+				},
+			},
+		},
+		{
+			name: "given sitemaps, expect their URLs crawled and only linked pages marked as linked",
+			givenOptions: []Option{WithSitemaps(mockSitemaps{
+				GivenURLs: []*url.URL{
+					{Host: "example.com", Path: "/orphan/"},
+				},
+			})},
+			givenParser: mockParser{
+				GivenURLs: []*url.URL{
+					{Host: "example.com", Path: "/1/"},
+				},
+			},
+			expectedPages: []domain.Page{
+				{
+					URL:    url.URL{Host: "example.com"},
+					Seed:   url.URL{Host: "example.com"},
+					Source: domain.SourceSeed,
+					Links:  []url.URL{{Host: "example.com", Path: "/1/"}},
+				},
+				{
+					URL:      url.URL{Host: "example.com", Path: "/1/"},
+					Referrer: url.URL{Host: "example.com"},
+					Seed:     url.URL{Host: "example.com"},
+					Source:   domain.SourceLink,
+					Linked:   true,
+					Depth:    1,
+					Links:    []url.URL{{Host: "example.com", Path: "/1/"}},
+				},
+				{
+					URL:    url.URL{Host: "example.com", Path: "/orphan/"},
+					Seed:   url.URL{Host: "example.com"},
+					Source: domain.SourceSitemap,
+					Links:  []url.URL{{Host: "example.com", Path: "/1/"}},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewController(NewRepository(memory.New()), mockRepeatClient{}, test.givenParser, test.givenOptions...)
+
+			baseURL := &url.URL{Host: "example.com"}
+
+			actual, errs, err := c.Start(context.Background(), baseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].URL.Path < actual[j].URL.Path
+			})
+
+			if !cmp.Equal(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))))
+			}
+		})
+	}
+}
+
+func TestController_Start_Responses(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenOptions  []Option
+		givenResponse *http.Response
+		givenDocument domain.Document
+		expectedPage  domain.Page
+	}{
+		{
+			name: "given an unsupported content type, expect the body closed and not parsed",
+			givenResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/pdf"}},
+				Body:       &mockBody{Reader: strings.NewReader("%PDF-1.4")},
+			},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com"},
+				Seed:        url.URL{Host: "example.com"},
+				Source:      domain.SourceSeed,
+				StatusCode:  http.StatusOK,
+				ContentType: "application/pdf",
+				SkipReason:  "content type application/pdf is not supported",
+			},
+		},
+		{
+			name: "given a redirect to another host, expect the body closed and not parsed",
+			givenResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/html"}},
+				Body:       &mockBody{Reader: strings.NewReader(htmlBody)},
+				Request: &http.Request{
+					URL:      &url.URL{Host: "other.com", Path: "/"},
+					Response: &http.Response{Request: &http.Request{URL: &url.URL{Host: "example.com"}}},
+				},
+			},
+			givenDocument: domain.Document{Links: []*url.URL{{Host: "other.com", Path: "/a/"}}},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com"},
+				Seed:        url.URL{Host: "example.com"},
+				Source:      domain.SourceSeed,
+				StatusCode:  http.StatusOK,
+				ContentType: "text/html",
+				Redirects:   []url.URL{{Host: "other.com", Path: "/"}},
+				SkipReason:  "redirected to //other.com/, which is not crawled",
+			},
+		},
+		{
+			name:         "given a redirect out of scope, expect the body closed and not parsed",
+			givenOptions: []Option{WithScope(mockScope{GivenDisallowedPath: "/private/"})},
+			givenResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/html"}},
+				Body:       &mockBody{Reader: strings.NewReader(htmlBody)},
+				Request: &http.Request{
+					URL:      &url.URL{Host: "example.com", Path: "/private/"},
+					Response: &http.Response{Request: &http.Request{URL: &url.URL{Host: "example.com"}}},
+				},
+			},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com"},
+				Seed:        url.URL{Host: "example.com"},
+				Source:      domain.SourceSeed,
+				StatusCode:  http.StatusOK,
+				ContentType: "text/html",
+				Redirects:   []url.URL{{Host: "example.com", Path: "/private/"}},
+				SkipReason:  "redirected to //example.com/private/, which is not crawled",
+			},
+		},
+		{
+			name: "given a response over TLS, expect the certificate of its host recorded",
+			givenResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/pdf"}},
+				Body:       &mockBody{Reader: strings.NewReader("%PDF-1.4")},
+				Request:    &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}},
+				TLS: &tls.ConnectionState{
+					Version: tls.VersionTLS13,
+					PeerCertificates: []*x509.Certificate{
+						{
+							Subject:  pkix.Name{CommonName: "example.com"},
+							Issuer:   pkix.Name{CommonName: "Example CA"},
+							DNSNames: []string{"example.com"},
+							NotAfter: time.Date(2021, 6, 10, 16, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+			},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com"},
+				Seed:        url.URL{Host: "example.com"},
+				Source:      domain.SourceSeed,
+				StatusCode:  http.StatusOK,
+				ContentType: "application/pdf",
+				SkipReason:  "content type application/pdf is not supported",
+				Certificate: &domain.Certificate{
+					Host:       "example.com",
+					Subject:    "CN=example.com",
+					Issuer:     "CN=Example CA",
+					DNSNames:   []string{"example.com"},
+					NotAfter:   time.Date(2021, 6, 10, 16, 0, 0, 0, time.UTC),
+					TLSVersion: "TLS 1.3",
+				},
+			},
+		},
+		{
+			name:         "given a body larger than the max body size, expect it truncated and closed",
+			givenOptions: []Option{WithMaxBodySize(10)},
+			givenResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+				Body:       &mockBody{Reader: strings.NewReader(htmlBody)},
+			},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com"},
+				Seed:        url.URL{Host: "example.com"},
+				Source:      domain.SourceSeed,
+				StatusCode:  http.StatusOK,
+				ContentType: "text/html; charset=utf-8",
+				Truncated:   true,
+				SkipReason:  "body is larger than 10 bytes, only the start was parsed",
+			},
+		},
+		{
+			name: "given a parsed body with outlinks, metadata and text, expect them and its fingerprints on the page",
+			givenResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/html"}},
+				Body:       &mockBody{Reader: strings.NewReader(htmlBody)},
+			},
+			givenDocument: domain.Document{
+				Outlinks: []domain.Link{{URL: url.URL{Host: "example.com", Path: "/1/"}, Text: "link1"}},
+				Metadata: domain.Metadata{Title: "Links", WordCount: 3},
+				Text:     "link1 link2 link3",
+			},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com"},
+				Seed:        url.URL{Host: "example.com"},
+				Source:      domain.SourceSeed,
+				StatusCode:  http.StatusOK,
+				ContentType: "text/html",
+				Metadata:    domain.Metadata{Title: "Links", WordCount: 3},
+				Outlinks:    []domain.Link{{URL: url.URL{Host: "example.com", Path: "/1/"}, Text: "link1"}},
+				ContentHash: fingerprint.Hash("link1 link2 link3"),
+				SimHash:     fingerprint.SimHash("link1 link2 link3"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := &mockRecordingParser{GivenDocument: test.givenDocument}
+			c := NewController(
+				NewRepository(memory.New()),
+				&mockClient{GivenFetchResponse: test.givenResponse},
+				parser,
+				test.givenOptions...,
+			)
+
+			actual, errs, err := c.Start(context.Background(), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			if !cmp.Equal(actual, []domain.Page{test.expectedPage}, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))) {
+				t.Fatal(cmp.Diff(actual, []domain.Page{test.expectedPage}, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))))
+			}
+
+			if !test.givenResponse.Body.(*mockBody).closed {
+				t.Fatal("expected the body to be closed")
+			}
+
+			if test.expectedPage.Truncated && parser.read > 10 {
+				t.Fatalf("expected at most 10 bytes parsed, got %v", parser.read)
+			}
+
+			if test.expectedPage.SkipReason != "" && !test.expectedPage.Truncated && parser.called {
+				t.Fatal("expected the body not to be parsed")
+			}
+		})
+	}
+}
+
+func TestController_Start_Cached(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenCached   []domain.Page
+		expectedPages []domain.Page
+		expectedETags []string
+	}{
+		{
+			name: "given a cached page which is unchanged, expect its cached results and links reused",
+			givenCached: []domain.Page{
+				{
+					URL:         url.URL{Host: "example.com"},
+					StatusCode:  http.StatusOK,
+					ContentType: "text/html",
+					ETag:        `"v1"`,
+					Metadata:    domain.Metadata{Title: "Home"},
+					Links:       []url.URL{{Host: "example.com", Path: "/1/"}},
+				},
+				{
+					URL:        url.URL{Host: "example.com", Path: "/1/"},
+					StatusCode: http.StatusOK,
+				},
+			},
+			expectedPages: []domain.Page{
+				{
+					URL:         url.URL{Host: "example.com"},
+					Seed:        url.URL{Host: "example.com"},
+					Source:      domain.SourceSeed,
+					StatusCode:  http.StatusOK,
+					ContentType: "text/html",
+					ETag:        `"v1"`,
+					Unchanged:   true,
+					Metadata:    domain.Metadata{Title: "Home"},
+					Links:       []url.URL{{Host: "example.com", Path: "/1/"}},
+				},
+				{
+					URL:         url.URL{Host: "example.com", Path: "/1/"},
+					Referrer:    url.URL{Host: "example.com"},
+					Seed:        url.URL{Host: "example.com"},
+					Source:      domain.SourceLink,
+					Linked:      true,
+					Depth:       1,
+					StatusCode:  http.StatusOK,
+					ContentType: "text/html",
+					ETag:        `"v2"`,
+				},
+			},
+			expectedETags: []string{`"v1"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := NewRepository(memory.New())
+			for _, page := range test.givenCached {
+				if _, err := cache.Insert(page); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			client := &mockConditionalClient{}
+			c := NewController(NewRepository(memory.New()), client, mockParser{}, WithCache(cache), WithConcurrency(1))
+
+			actual, errs, err := c.Start(context.Background(), &url.URL{Host: "example.com"})
+			if err != nil {
 				t.Fatal(err)
 			}
 
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
 			sort.Slice(actual, func(i, j int) bool {
 				return actual[i].URL.Path < actual[j].URL.Path
 			})
 
-			if !cmp.Equal(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{})) {
-				t.Fatal(cmp.Diff(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{})))
+			if !cmp.Equal(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))))
+			}
+
+			if !cmp.Equal(client.etags, test.expectedETags) {
+				t.Fatal(cmp.Diff(client.etags, test.expectedETags))
+			}
+		})
+	}
+}
+
+func TestController_Start_Unacceptable(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenError    error
+		expectedPage  domain.Page
+		expectedError error
+	}{
+		{
+			name: "given a redirect to a missing page, expect the status code and redirects on the page",
+			givenError: &httpclient.StatusError{
+				StatusCode: http.StatusNotFound,
+				Redirects:  []url.URL{{Host: "example.com", Path: "/moved/"}},
+			},
+			expectedPage: domain.Page{
+				URL:        url.URL{Host: "example.com"},
+				Seed:       url.URL{Host: "example.com"},
+				Source:     domain.SourceSeed,
+				StatusCode: http.StatusNotFound,
+				Redirects:  []url.URL{{Host: "example.com", Path: "/moved/"}},
+			},
+			expectedError: httpclient.ErrUnacceptableResponse,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewController(NewRepository(memory.New()), mockErrorClient{GivenError: test.givenError}, mockParser{})
+
+			actual, errs, err := c.Start(context.Background(), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(error(errs), test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(error(errs), test.expectedError, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(actual, []domain.Page{test.expectedPage}, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))) {
+				t.Fatal(cmp.Diff(actual, []domain.Page{test.expectedPage}, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))))
+			}
+		})
+	}
+}
+
+func TestController_Start_FetchDuration(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenClient ClientProvider
+	}{
+		{
+			name:        "given a slow response, expect the fetch to be timed until it was received",
+			givenClient: mockSlowClient{GivenResponseDelay: 20 * time.Millisecond},
+		},
+		{
+			name:        "given a slow body, expect the fetch to be timed until it was read",
+			givenClient: mockSlowClient{GivenBodyDelay: 20 * time.Millisecond},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewController(NewRepository(memory.New()), test.givenClient, mockParser{})
+
+			actual, _, err := c.Start(context.Background(), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(actual) != 1 || actual[0].FetchDuration < 20*time.Millisecond {
+				t.Fatalf("expected a fetch duration of at least 20ms, got %v", actual)
+			}
+		})
+	}
+}
+
+func TestController_Start_Metrics(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenClient     ClientProvider
+		givenParser     Parser
+		expectedMetrics *mockMetrics
+	}{
+		{
+			name: "given a page linking to two missing pages, expect three fetched with two status errors",
+			givenClient: &mockClient{
+				GivenFetchResponse: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(strings.NewReader("hello")),
+				},
+				GivenFetchError: &httpclient.StatusError{StatusCode: http.StatusNotFound},
+			},
+			givenParser: mockParser{
+				GivenURLs: []*url.URL{{Host: "example.com", Path: "/1/"}, {Host: "example.com", Path: "/2/"}},
+			},
+			expectedMetrics: &mockMetrics{
+				queued:   3,
+				started:  3,
+				finished: 3,
+				errors:   map[string]int{ErrorClassStatus: 2},
+				bytes:    5,
+			},
+		},
+		{
+			name:        "given a request which times out, expect a timeout error",
+			givenClient: mockErrorClient{GivenError: fmt.Errorf("deadline: %w", httpclient.ErrTimeout)},
+			givenParser: mockParser{},
+			expectedMetrics: &mockMetrics{
+				queued:   1,
+				started:  1,
+				finished: 1,
+				errors:   map[string]int{ErrorClassTimeout: 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := &mockMetrics{errors: make(map[string]int)}
+			c := NewController(NewRepository(memory.New()), test.givenClient, test.givenParser, WithMetrics(metrics), WithConcurrency(1))
+
+			_, _, _ = c.Start(context.Background(), &url.URL{Host: "example.com"})
+
+			if !cmp.Equal(metrics, test.expectedMetrics, cmp.AllowUnexported(mockMetrics{}), cmpopts.IgnoreTypes(sync.Mutex{})) {
+				t.Fatal(cmp.Diff(metrics, test.expectedMetrics, cmp.AllowUnexported(mockMetrics{}), cmpopts.IgnoreTypes(sync.Mutex{})))
+			}
+		})
+	}
+}
+
+func TestController_Start_Logger(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenClient     ClientProvider
+		givenParser     Parser
+		expectedEntries []log.Fields
+	}{
+		{
+			name: "given a page linking to a missing page, expect a line for each with its fields",
+			givenClient: &mockClient{
+				GivenFetchResponse: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(strings.NewReader("hello")),
+				},
+				GivenFetchError: &httpclient.StatusError{StatusCode: http.StatusNotFound},
+			},
+			givenParser: mockParser{
+				GivenURLs: []*url.URL{{Host: "example.com", Path: "/1/"}},
+			},
+			expectedEntries: []log.Fields{
+				{
+					"msg":      "crawled page",
+					"url":      "//example.com",
+					"referrer": "",
+					"depth":    0,
+					"status":   http.StatusOK,
+				},
+				{
+					"msg":      "failed to crawl page",
+					"url":      "//example.com/1/",
+					"referrer": "//example.com",
+					"depth":    1,
+					"status":   http.StatusNotFound,
+					"error":    "fetch //example.com/1/: 404: invalid status code received",
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := logtest.NewNullLogger()
+			logger.SetLevel(log.DebugLevel)
+
+			c := NewController(NewRepository(memory.New()), test.givenClient, test.givenParser, WithLogger(logger))
+
+			_, _, _ = c.Start(context.Background(), &url.URL{Host: "example.com"})
+
+			var actual []log.Fields
+
+			for _, entry := range hook.AllEntries() {
+				fields := log.Fields{"msg": entry.Message}
+
+				for key, value := range entry.Data {
+					switch key {
+					case "duration":
+						continue
+					case "error":
+						value = value.(error).Error()
+					}
+
+					fields[key] = value
+				}
+
+				actual = append(actual, fields)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i]["url"].(string) < actual[j]["url"].(string)
+			})
+
+			if !cmp.Equal(actual, test.expectedEntries) {
+				t.Fatal(cmp.Diff(actual, test.expectedEntries))
+			}
+		})
+	}
+}
+
+func TestController_Start_Tracer(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenClient   ClientProvider
+		givenParser   Parser
+		expectedSpans []string
+	}{
+		{
+			name: "given a page linking to another, expect the linked page's spans to be children of the first's",
+			givenClient: &mockClient{
+				GivenFetchResponse: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(strings.NewReader("hello")),
+				},
+				GivenFetchError: &httpclient.StatusError{StatusCode: http.StatusNotFound},
+			},
+			givenParser: mockParser{
+				GivenURLs: []*url.URL{{Host: "example.com", Path: "/1/"}},
+			},
+			expectedSpans: []string{
+				"Repository.Get //example.com <- root",
+				"Repository.Insert //example.com <- root",
+				"crawl page //example.com <- root",
+				"Client.Fetch //example.com <- crawl page //example.com",
+				"Parser.Parse //example.com <- crawl page //example.com",
+				"Repository.Update //example.com <- crawl page //example.com",
+				"Repository.Get //example.com/1/ <- crawl page //example.com",
+				"Repository.Insert //example.com/1/ <- crawl page //example.com",
+				"crawl page //example.com/1/ <- crawl page //example.com",
+				"Client.Fetch //example.com/1/ <- crawl page //example.com/1/",
+				"Repository.Update //example.com/1/ <- crawl page //example.com/1/",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			provider := tracing.NewWithExporter(exporter)
+
+			ctx, root := provider.Tracer("test").Start(context.Background(), "root")
+
+			c := NewController(NewRepository(memory.New()), test.givenClient, test.givenParser, WithTracer(provider))
+
+			_, _, _ = c.Start(ctx, &url.URL{Host: "example.com"})
+
+			root.End()
+
+			spans := exporter.GetSpans()
+
+			names := make(map[string]string)
+			for _, span := range spans {
+				names[span.SpanContext.SpanID().String()] = describeSpan(span)
+			}
+
+			sort.SliceStable(spans, func(i, j int) bool {
+				return spanOrder(describeSpan(spans[i])) < spanOrder(describeSpan(spans[j]))
+			})
+
+			var actual []string
+			for _, span := range spans {
+				if span.Name == "root" {
+					continue
+				}
+
+				actual = append(actual, fmt.Sprintf("%v <- %v", describeSpan(span), names[span.Parent.SpanID().String()]))
+			}
+
+			if !cmp.Equal(actual, test.expectedSpans) {
+				t.Fatal(cmp.Diff(actual, test.expectedSpans))
+			}
+		})
+	}
+}
+
+// describeSpan names a span by what it did and the url it did it to.
+func describeSpan(span tracetest.SpanStub) string {
+	for _, attr := range span.Attributes {
+		if attr.Key == "url" {
+			return fmt.Sprintf("%v %v", span.Name, attr.Value.AsString())
+		}
+	}
+
+	return span.Name
+}
+
+// spanOrder orders spans by their url, then in the order a page is crawled, as ended spans are exported out of order.
+func spanOrder(description string) string {
+	steps := []string{"Repository.Get", "Repository.Insert", "crawl page", "Client.Fetch", "Parser.Parse", "Repository.Update"}
+
+	fields := strings.Fields(description)
+	u := fields[len(fields)-1]
+
+	for i, step := range steps {
+		if strings.HasPrefix(description, step) {
+			return fmt.Sprintf("%v %v", u, i)
+		}
+	}
+
+	return u
+}
+
+func TestController_Start_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenRepo      RepositoryProvider
+		givenClient    ClientProvider
+		givenParser    Parser
+		givenOptions   []Option
+		expectedErrors domain.CrawlErrors
+	}{
+		{
+			name:      "given a page linking to a missing page, expect a fetch error with its referrer and status",
+			givenRepo: NewRepository(memory.New()),
+			givenClient: &mockClient{
+				GivenFetchResponse: &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(strings.NewReader("hello")),
+				},
+				GivenFetchError: &httpclient.StatusError{StatusCode: http.StatusNotFound},
+			},
+			givenParser: mockParser{
+				GivenURLs: []*url.URL{{Host: "example.com", Path: "/1/"}},
+			},
+			expectedErrors: domain.CrawlErrors{
+				{
+					URL:      url.URL{Host: "example.com", Path: "/1/"},
+					Referrer: url.URL{Host: "example.com"},
+					Phase:    domain.PhaseFetch,
+					Status:   http.StatusNotFound,
+					Err:      &httpclient.StatusError{StatusCode: http.StatusNotFound},
+				},
+			},
+		},
+		{
+			name:        "given a request which cannot be built, expect a build error",
+			givenRepo:   NewRepository(memory.New()),
+			givenClient: mockErrorClient{GivenError: httpclient.ErrFailedToBuildRequest},
+			givenParser: mockParser{},
+			expectedErrors: domain.CrawlErrors{
+				{
+					URL:   url.URL{Host: "example.com"},
+					Phase: domain.PhaseBuild,
+					Err:   httpclient.ErrFailedToBuildRequest,
+				},
+			},
+		},
+		{
+			name:      "given a body which cannot be parsed, expect a parse error with its status",
+			givenRepo: NewRepository(memory.New()),
+			givenClient: &mockClient{
+				GivenFetchResponse: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(htmlBody)),
+				},
+			},
+			givenParser: mockParser{GivenError: htmlparser.ErrFailedToParseHTML},
+			expectedErrors: domain.CrawlErrors{
+				{
+					URL:    url.URL{Host: "example.com"},
+					Phase:  domain.PhaseParse,
+					Status: http.StatusOK,
+					Err:    htmlparser.ErrFailedToParseHTML,
+				},
+			},
+		},
+		{
+			name: "given a page which cannot be stored, expect a store error",
+			givenRepo: mockRepo{
+				GivenGetError:    memory.ErrInvalidKey,
+				GivenInsertError: errors.New("disk full"),
+			},
+			givenClient: &mockClient{},
+			givenParser: mockParser{},
+			expectedErrors: domain.CrawlErrors{
+				{
+					URL:   url.URL{Host: "example.com"},
+					Phase: domain.PhaseStore,
+					Err:   errors.New("disk full"),
+				},
+			},
+		},
+		{
+			name:      "given a sitemap which cannot be fetched, expect a sitemap error for it linked from the seed",
+			givenRepo: NewRepository(memory.New()),
+			givenClient: &mockClient{
+				GivenFetchResponse: &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(htmlBody)),
+				},
+			},
+			givenParser: mockParser{},
+			givenOptions: []Option{WithSitemaps(mockSitemaps{
+				GivenError: &sitemap.LoadError{
+					URL: url.URL{Host: "example.com", Path: "/sitemap.xml"},
+					Err: &httpclient.StatusError{StatusCode: http.StatusNotFound},
+				},
+			})},
+			expectedErrors: domain.CrawlErrors{
+				{
+					URL:      url.URL{Host: "example.com", Path: "/sitemap.xml"},
+					Referrer: url.URL{Host: "example.com"},
+					Phase:    domain.PhaseSitemap,
+					Status:   http.StatusNotFound,
+					Err:      &httpclient.StatusError{StatusCode: http.StatusNotFound},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewController(test.givenRepo, test.givenClient, test.givenParser, test.givenOptions...)
+
+			_, errs, err := c.Start(context.Background(), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(errs, test.expectedErrors, cmpopts.IgnoreFields(domain.CrawlError{}, "Err")) {
+				t.Fatal(cmp.Diff(errs, test.expectedErrors, cmpopts.IgnoreFields(domain.CrawlError{}, "Err")))
+			}
+
+			for i := range errs {
+				if !cmp.Equal(errs[i].Err.Error(), test.expectedErrors[i].Err.Error()) {
+					t.Fatal(cmp.Diff(errs[i].Err.Error(), test.expectedErrors[i].Err.Error()))
+				}
+			}
+		})
+	}
+}
+
+func TestController_Start_Cancelled(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenTimeout  time.Duration
+		expectedPages []domain.Page
+		expectedError error
+	}{
+		{
+			name:         "given the context is cancelled mid-crawl, expect the pages found so far and the context error",
+			givenTimeout: 50 * time.Millisecond,
+			expectedPages: []domain.Page{
+				{
+					URL:     url.URL{Host: "example.com"},
+					Seed:    url.URL{Host: "example.com"},
+					Source:  domain.SourceSeed,
+					Pending: true,
+				},
+				{
+					URL:     url.URL{Host: "example.org"},
+					Seed:    url.URL{Host: "example.org"},
+					Source:  domain.SourceSeed,
+					Pending: true,
+				},
+			},
+			expectedError: context.DeadlineExceeded,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewController(NewRepository(memory.New()), mockBlockingClient{}, mockParser{}, WithConcurrency(1))
+
+			ctx, cancel := context.WithTimeout(context.Background(), test.givenTimeout)
+			defer cancel()
+
+			actual, _, err := c.Start(ctx, &url.URL{Host: "example.com"}, &url.URL{Host: "example.org"})
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages, cmpopts.IgnoreTypes(time.Time{}, time.Duration(0))))
 			}
 		})
 	}
@@ -123,7 +1053,9 @@ func TestController_Start_Fail(t *testing.T) {
 			givenBaseURL: &url.URL{
 				Host: "example.com",
 			},
-			givenRepo: mockRepo{},
+			givenRepo: mockRepo{
+				GivenGetError: memory.ErrInvalidKey,
+			},
 			givenClient: &mockClient{
 				GivenFetchResponse: &http.Response{
 					Body: io.NopCloser(strings.NewReader(htmlBody)),
@@ -139,9 +1071,13 @@ func TestController_Start_Fail(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			c := NewController(test.givenRepo, test.givenClient, test.givenParser)
 
-			_, err := c.Start(context.Background(), test.givenBaseURL, test.givenBaseURL)
-			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
-				t.Fatal(cmp.Diff(err, test.expectedError))
+			_, errs, err := c.Start(context.Background(), test.givenBaseURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(error(errs), test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(error(errs), test.expectedError, cmpopts.EquateErrors()))
 			}
 		})
 	}
@@ -152,6 +1088,8 @@ type mockRepo struct {
 	GivenGetError    error
 	GivenInsertPage  domain.Page
 	GivenInsertError error
+	GivenUpdatePage  domain.Page
+	GivenUpdateError error
 }
 
 func (m mockRepo) Get(_ url.URL) (domain.Page, error) {
@@ -162,6 +1100,77 @@ func (m mockRepo) Insert(_ domain.Page) (domain.Page, error) {
 	return m.GivenInsertPage, m.GivenInsertError
 }
 
+func (m mockRepo) Update(_ domain.Page) (domain.Page, error) {
+	return m.GivenUpdatePage, m.GivenUpdateError
+}
+
+// mockConditionalClient responds 304 to every conditional request, and otherwise 200 with a new ETag.
+type mockConditionalClient struct {
+	etags []string
+}
+
+func (m *mockConditionalClient) Fetch(_ context.Context, _ url.URL) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html"}, "Etag": {`"v2"`}},
+		Body:       io.NopCloser(strings.NewReader("<html></html>")),
+	}, nil
+}
+
+func (m *mockConditionalClient) FetchIfModified(_ context.Context, _ url.URL, etag, _ string) (*http.Response, error) {
+	m.etags = append(m.etags, etag)
+
+	return &http.Response{
+		StatusCode: http.StatusNotModified,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+type mockMetrics struct {
+	mu       sync.Mutex
+	queued   int
+	started  int
+	finished int
+	errors   map[string]int
+	bytes    int
+}
+
+func (m *mockMetrics) PageQueued() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queued++
+}
+
+func (m *mockMetrics) FetchStarted() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started++
+}
+
+func (m *mockMetrics) FetchFinished(errorClass string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished++
+
+	if errorClass != "" {
+		m.errors[errorClass]++
+	}
+}
+
+func (m *mockMetrics) BodyRead(bytes int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes += bytes
+}
+
+type mockErrorClient struct {
+	GivenError error
+}
+
+func (m mockErrorClient) Fetch(_ context.Context, _ url.URL) (*http.Response, error) {
+	return nil, m.GivenError
+}
+
 type mockClient struct {
 	GivenFetchResponse *http.Response
 	GivenFetchError    error
@@ -177,15 +1186,130 @@ func (m *mockClient) Fetch(_ context.Context, _ url.URL) (*http.Response, error)
 	return nil, m.GivenFetchError
 }
 
-type mockParser struct {
+type mockRepeatClient struct{}
+
+func (m mockRepeatClient) Fetch(_ context.Context, _ url.URL) (*http.Response, error) {
+	return &http.Response{
+		Body: io.NopCloser(strings.NewReader(htmlBody)),
+	}, nil
+}
+
+// mockSlowClient waits before responding, and before the end of the body.
+type mockSlowClient struct {
+	GivenResponseDelay time.Duration
+	GivenBodyDelay     time.Duration
+}
+
+func (m mockSlowClient) Fetch(_ context.Context, _ url.URL) (*http.Response, error) {
+	time.Sleep(m.GivenResponseDelay)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       io.NopCloser(io.MultiReader(strings.NewReader(htmlBody), slowReader{m.GivenBodyDelay})),
+	}, nil
+}
+
+// slowReader waits before reaching the end of what it reads.
+type slowReader struct {
+	delay time.Duration
+}
+
+func (r slowReader) Read(_ []byte) (int, error) {
+	time.Sleep(r.delay)
+
+	return 0, io.EOF
+}
+
+type mockSitemaps struct {
 	GivenURLs  []*url.URL
 	GivenError error
 }
 
-func (m mockParser) FetchLinks(_ io.Reader, _ *url.URL) ([]*url.URL, error) {
+func (m mockSitemaps) Discover(_ context.Context, _ url.URL) ([]*url.URL, error) {
 	return m.GivenURLs, m.GivenError
 }
 
+// mockBlockingClient never responds until the request's context is done.
+type mockBlockingClient struct{}
+
+func (m mockBlockingClient) Fetch(ctx context.Context, _ url.URL) (*http.Response, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+type mockBody struct {
+	io.Reader
+	closed bool
+}
+
+func (m *mockBody) Close() error {
+	m.closed = true
+
+	return nil
+}
+
+// mockRecordingParser records how many bytes it was given and finds no links.
+type mockRecordingParser struct {
+	GivenDocument domain.Document
+	called        bool
+	read          int
+}
+
+func (m *mockRecordingParser) Supports(contentType string) bool {
+	return contentType != "application/pdf"
+}
+
+func (m *mockRecordingParser) Parse(_ context.Context, body io.Reader, _ string, _ *url.URL) (domain.Document, error) {
+	b, err := io.ReadAll(body)
+	m.called = true
+	m.read = len(b)
+
+	return m.GivenDocument, err
+}
+
+type mockScope struct {
+	GivenDisallowedPath string
+}
+
+func (m mockScope) Allows(u url.URL) bool {
+	return u.Path != m.GivenDisallowedPath
+}
+
+// mockChainParser returns a single, previously unseen link on every call.
+type mockChainParser struct {
+	calls int
+}
+
+func (m *mockChainParser) Supports(_ string) bool {
+	return true
+}
+
+func (m *mockChainParser) Parse(_ context.Context, _ io.Reader, _ string, _ *url.URL) (domain.Document, error) {
+	m.calls++
+
+	return domain.Document{
+		Links: []*url.URL{
+			{Host: "example.com", Path: fmt.Sprintf("/%d/", m.calls)},
+		},
+	}, nil
+}
+
+type mockParser struct {
+	GivenURLs     []*url.URL
+	GivenMetadata domain.Metadata
+	GivenError    error
+}
+
+func (m mockParser) Parse(_ context.Context, _ io.Reader, _ string, _ *url.URL) (domain.Document, error) {
+	return domain.Document{Links: m.GivenURLs, Metadata: m.GivenMetadata}, m.GivenError
+}
+
+func (m mockParser) Supports(_ string) bool {
+	return true
+}
+
 var htmlBody = `
 	<html><a href='https://example.com/1'>link1</a>
 	<a href='https://example.com/2'>link2</a>
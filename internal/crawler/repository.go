@@ -10,6 +10,8 @@ import (
 type StorageProvider interface {
 	Get(url url.URL) (storage.Page, error)
 	Insert(page storage.Page) error
+	Update(page storage.Page) error
+	All() []storage.Page
 }
 
 // Repository adapts between domain and storage models.
@@ -44,18 +46,146 @@ func (r Repository) Insert(page domain.Page) (domain.Page, error) {
 	return page, nil
 }
 
+// Update replaces the stored domain.Page with the same URL.
+func (r Repository) Update(page domain.Page) (domain.Page, error) {
+	err := r.Storage.Update(adaptStorageFromDomain(page))
+	if err != nil {
+		return domain.Page{}, err
+	}
+
+	return page, nil
+}
+
+// All returns every stored domain.Page.
+func (r Repository) All() []domain.Page {
+	stored := r.Storage.All()
+
+	pages := make([]domain.Page, len(stored))
+	for i := range stored {
+		pages[i] = adaptStorageToDomain(stored[i])
+	}
+
+	return pages
+}
+
 func adaptStorageToDomain(page storage.Page) domain.Page {
 	return domain.Page{
-		URL:       page.URL,
-		Referrer:  page.Referrer,
-		CrawledAt: page.CrawledAt,
+		URL:           page.URL,
+		Referrer:      page.Referrer,
+		Seed:          page.Seed,
+		Source:        domain.Source(page.Source),
+		Depth:         page.Depth,
+		CrawledAt:     page.CrawledAt,
+		FetchDuration: page.FetchDuration,
+		StatusCode:    page.StatusCode,
+		Redirects:     page.Redirects,
+		ContentType:   page.ContentType,
+		ETag:          page.ETag,
+		LastModified:  page.LastModified,
+		Unchanged:     page.Unchanged,
+		Truncated:     page.Truncated,
+		SkipReason:    page.SkipReason,
+		Metadata:      adaptStorageMetadataToDomain(page.Metadata),
+		ContentHash:   page.ContentHash,
+		SimHash:       page.SimHash,
+		Outlinks:      adaptStorageLinksToDomain(page.Outlinks),
+		Links:         page.Links,
 	}
 }
 
 func adaptStorageFromDomain(page domain.Page) storage.Page {
 	return storage.Page{
-		URL:       page.URL,
-		Referrer:  page.Referrer,
-		CrawledAt: page.CrawledAt,
+		URL:           page.URL,
+		Referrer:      page.Referrer,
+		Seed:          page.Seed,
+		Source:        string(page.Source),
+		Depth:         page.Depth,
+		CrawledAt:     page.CrawledAt,
+		FetchDuration: page.FetchDuration,
+		StatusCode:    page.StatusCode,
+		Redirects:     page.Redirects,
+		ContentType:   page.ContentType,
+		ETag:          page.ETag,
+		LastModified:  page.LastModified,
+		Unchanged:     page.Unchanged,
+		Truncated:     page.Truncated,
+		SkipReason:    page.SkipReason,
+		Metadata:      adaptStorageMetadataFromDomain(page.Metadata),
+		ContentHash:   page.ContentHash,
+		SimHash:       page.SimHash,
+		Outlinks:      adaptStorageLinksFromDomain(page.Outlinks),
+		Links:         page.Links,
+	}
+}
+
+func adaptStorageLinksToDomain(links []storage.Link) []domain.Link {
+	var adapted []domain.Link
+
+	for _, l := range links {
+		adapted = append(adapted, domain.Link{
+			URL:      l.URL,
+			Text:     l.Text,
+			Title:    l.Title,
+			Rel:      l.Rel,
+			Target:   l.Target,
+			Location: domain.Location(l.Location),
+		})
+	}
+
+	return adapted
+}
+
+func adaptStorageLinksFromDomain(links []domain.Link) []storage.Link {
+	var adapted []storage.Link
+
+	for _, l := range links {
+		adapted = append(adapted, storage.Link{
+			URL:      l.URL,
+			Text:     l.Text,
+			Title:    l.Title,
+			Rel:      l.Rel,
+			Target:   l.Target,
+			Location: string(l.Location),
+		})
+	}
+
+	return adapted
+}
+
+func adaptStorageMetadataToDomain(m storage.Metadata) domain.Metadata {
+	var alternates []domain.Alternate
+
+	for _, a := range m.Alternates {
+		alternates = append(alternates, domain.Alternate{Lang: a.Lang, URL: a.URL})
+	}
+
+	return domain.Metadata{
+		Title:       m.Title,
+		Description: m.Description,
+		Canonical:   m.Canonical,
+		Alternates:  alternates,
+		H1:          m.H1,
+		H2:          m.H2,
+		Lang:        m.Lang,
+		WordCount:   m.WordCount,
+	}
+}
+
+func adaptStorageMetadataFromDomain(m domain.Metadata) storage.Metadata {
+	var alternates []storage.Alternate
+
+	for _, a := range m.Alternates {
+		alternates = append(alternates, storage.Alternate{Lang: a.Lang, URL: a.URL})
+	}
+
+	return storage.Metadata{
+		Title:       m.Title,
+		Description: m.Description,
+		Canonical:   m.Canonical,
+		Alternates:  alternates,
+		H1:          m.H1,
+		H2:          m.H2,
+		Lang:        m.Lang,
+		WordCount:   m.WordCount,
 	}
 }
@@ -41,6 +41,44 @@ func TestRepository_Get_Success(t *testing.T) {
 				CrawledAt: time.Date(2021, 6, 9, 11, 00, 00, 00, time.UTC),
 			},
 		},
+		{
+			name:     "Given a URL with metadata, expect a page returned with its metadata",
+			givenURL: url.URL{Host: "https://example.com"},
+			givenStorage: mockStorage{
+				GivenGetPage: storage.Page{
+					URL: url.URL{Host: "example.com", Path: "/test/"},
+					Metadata: storage.Metadata{
+						Title:     "Test",
+						Canonical: url.URL{Host: "example.com", Path: "/test/"},
+						Alternates: []storage.Alternate{
+							{Lang: "fr", URL: url.URL{Host: "example.com", Path: "/fr/test/"}},
+						},
+						H1:        []string{"Test"},
+						Lang:      "en",
+						WordCount: 2,
+					},
+					Outlinks: []storage.Link{
+						{URL: url.URL{Host: "example.com", Path: "/"}, Text: "Home", Location: "nav"},
+					},
+				},
+			},
+			expectedPage: domain.Page{
+				URL: url.URL{Host: "example.com", Path: "/test/"},
+				Metadata: domain.Metadata{
+					Title:     "Test",
+					Canonical: url.URL{Host: "example.com", Path: "/test/"},
+					Alternates: []domain.Alternate{
+						{Lang: "fr", URL: url.URL{Host: "example.com", Path: "/fr/test/"}},
+					},
+					H1:        []string{"Test"},
+					Lang:      "en",
+					WordCount: 2,
+				},
+				Outlinks: []domain.Link{
+					{URL: url.URL{Host: "example.com", Path: "/"}, Text: "Home", Location: domain.LocationNav},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -173,10 +211,84 @@ func TestRepository_Insert_Fail(t *testing.T) {
 	}
 }
 
+func TestRepository_Update_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenPage    domain.Page
+		givenStorage StorageProvider
+		expectedPage domain.Page
+	}{
+		{
+			name: "Given a fetched page, expect it updated and returned",
+			givenPage: domain.Page{
+				URL:         url.URL{Host: "example.com", Path: "/test/"},
+				StatusCode:  200,
+				ContentType: "text/html",
+			},
+			givenStorage: mockStorage{},
+			expectedPage: domain.Page{
+				URL:         url.URL{Host: "example.com", Path: "/test/"},
+				StatusCode:  200,
+				ContentType: "text/html",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := NewRepository(test.givenStorage)
+
+			actual, err := repo.Update(test.givenPage)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedPage) {
+				t.Fatal(cmp.Diff(actual, test.expectedPage))
+			}
+		})
+	}
+}
+
+func TestRepository_Update_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenPage     domain.Page
+		givenStorage  StorageProvider
+		expectedError error
+	}{
+		{
+			name: "Given a page which has not been inserted, expect error to be returned",
+			givenPage: domain.Page{
+				URL: url.URL{Host: "example.com", Path: "/test/"},
+			},
+			givenStorage: mockStorage{
+				GivenUpdateError: memory.ErrInvalidKey,
+			},
+			expectedError: memory.ErrInvalidKey,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := NewRepository(test.givenStorage)
+
+			_, err := repo.Update(test.givenPage)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
 type mockStorage struct {
 	GivenGetPage     storage.Page
 	GivenGetError    error
 	GivenInsertError error
+	GivenUpdateError error
+	GivenAllPages    []storage.Page
 }
 
 func (m mockStorage) Get(_ url.URL) (storage.Page, error) {
@@ -186,3 +298,11 @@ func (m mockStorage) Get(_ url.URL) (storage.Page, error) {
 func (m mockStorage) Insert(_ storage.Page) error {
 	return m.GivenInsertError
 }
+
+func (m mockStorage) Update(_ storage.Page) error {
+	return m.GivenUpdateError
+}
+
+func (m mockStorage) All() []storage.Page {
+	return m.GivenAllPages
+}
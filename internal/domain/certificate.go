@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Certificate is the TLS certificate a host presented when a Page was fetched from it.
+type Certificate struct {
+	Host       string
+	Subject    string
+	Issuer     string
+	DNSNames   []string
+	NotBefore  time.Time
+	NotAfter   time.Time
+	TLSVersion string
+	// DaysRemaining is how many days the certificate was still valid for when it was crawled, which is negative once
+	// it has expired. It is only set in a Report.
+	DaysRemaining int
+}
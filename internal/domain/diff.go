@@ -0,0 +1,41 @@
+package domain
+
+import "net/url"
+
+// Diff is what changed between two crawls of the same site.
+type Diff struct {
+	Added            []url.URL
+	Removed          []url.URL
+	StatusChanges    []StatusChange
+	BrokenLinks      []BrokenLink
+	TitleChanges     []Change
+	CanonicalChanges []Change
+	Redirects        []Redirect
+}
+
+// StatusChange is a page which responded with a different status code.
+type StatusChange struct {
+	URL url.URL
+	Old int
+	New int
+}
+
+// BrokenLink is a link to a page which could not be fetched, or responded with an error, where it did not before.
+type BrokenLink struct {
+	URL        url.URL
+	Referrer   url.URL
+	StatusCode int
+}
+
+// Change is a value of a page which differs between two crawls.
+type Change struct {
+	URL url.URL
+	Old string
+	New string
+}
+
+// Redirect is a page which now redirects somewhere it did not before.
+type Redirect struct {
+	URL    url.URL
+	Target url.URL
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "net/url"
+
+// Document is everything found when parsing the body of a Page. Links are the URLs to crawl, and Outlinks each
+// anchor they were found in, if the parser has them. Text is the main text of the body, without boilerplate such as
+// navigation, if the parser can tell them apart.
+type Document struct {
+	Links    []*url.URL
+	Outlinks []Link
+	Metadata Metadata
+	Text     string
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Phase is the step of crawling a Page which failed.
+type Phase string
+
+// Phases a CrawlError can happen in: requesting the URL, parsing its body, building the request, storing the Page or
+// loading a sitemap of a seed.
+const (
+	PhaseFetch   Phase = "fetch"
+	PhaseParse   Phase = "parse"
+	PhaseBuild   Phase = "build"
+	PhaseStore   Phase = "store"
+	PhaseSitemap Phase = "sitemap"
+)
+
+// CrawlError is an error which happened while crawling a URL. It wraps its cause.
+type CrawlError struct {
+	URL      url.URL
+	Referrer url.URL
+	Phase    Phase
+	// Status is the status code of the response, if one was received.
+	Status int
+	Err    error
+}
+
+func (e *CrawlError) Error() string {
+	return fmt.Sprintf("%v %v: %v", e.Phase, e.URL.String(), e.Err)
+}
+
+func (e *CrawlError) Unwrap() error {
+	return e.Err
+}
+
+// CrawlErrors is every CrawlError of a crawl, in the order they happened. It matches a target with errors.Is or
+// errors.As if any of its CrawlErrors does.
+type CrawlErrors []CrawlError
+
+func (e CrawlErrors) Error() string {
+	messages := make([]string, len(e))
+
+	for i := range e {
+		messages[i] = e[i].Error()
+	}
+
+	return fmt.Sprintf("%v errors: %v", len(e), strings.Join(messages, "; "))
+}
+
+// Is reports whether any of the CrawlErrors is target.
+func (e CrawlErrors) Is(target error) bool {
+	for i := range e {
+		if errors.Is(&e[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the CrawlErrors which matches target.
+func (e CrawlErrors) As(target interface{}) bool {
+	for i := range e {
+		if errors.As(&e[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "net/url"
+
+// Finding is a problem found with a Page by an audit rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	URL      url.URL
+	Message  string
+}
+
+// Severity describes how much a Finding matters.
+type Severity string
+
+var (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
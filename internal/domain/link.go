@@ -0,0 +1,24 @@
+package domain
+
+import "net/url"
+
+// Link is an anchor found on a Page, along with how it was presented.
+type Link struct {
+	URL      url.URL
+	Text     string
+	Title    string
+	Rel      []string
+	Target   string
+	Location Location
+}
+
+// Location describes which part of a Page a Link was found in.
+type Location string
+
+var (
+	LocationNav    Location = "nav"
+	LocationHeader Location = "header"
+	LocationFooter Location = "footer"
+	LocationAside  Location = "aside"
+	LocationBody   Location = "body"
+)
@@ -9,5 +9,54 @@ import (
 type Page struct {
 	URL       url.URL
 	Referrer  url.URL
+	Seed      url.URL
+	Source    Source
+	Linked    bool
+	Depth     int
 	CrawledAt time.Time
+	// FetchDuration is how long the request took, until its body was read, or its headers if the body was not read.
+	FetchDuration time.Duration
+	StatusCode    int
+	Redirects     []url.URL
+	ContentType   string
+	ETag          string
+	LastModified  string
+	Unchanged     bool
+	// Pending is whether the page was queued but never fetched, because the crawl was stopped early.
+	Pending     bool
+	Truncated   bool
+	SkipReason  string
+	Metadata    Metadata
+	ContentHash string
+	SimHash     uint64
+	Outlinks    []Link
+	Links       []url.URL
+	Certificate *Certificate
 }
+
+// Metadata is what a HTML Page says about itself.
+type Metadata struct {
+	Title       string
+	Description string
+	Canonical   url.URL
+	Alternates  []Alternate
+	H1          []string
+	H2          []string
+	Lang        string
+	WordCount   int
+}
+
+// Alternate is a translation of a Page, given by a hreflang link.
+type Alternate struct {
+	Lang string
+	URL  url.URL
+}
+
+// Source describes how a Page was discovered.
+type Source string
+
+var (
+	SourceSeed    Source = "seed"
+	SourceLink    Source = "link"
+	SourceSitemap Source = "sitemap"
+)
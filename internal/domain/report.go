@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"net/url"
+)
+
+// Report is the domain representation of the results of a crawl.
+type Report struct {
+	Pages        []Page
+	Orphans      []url.URL
+	Findings     []Finding
+	Duplicates   []Duplicates
+	Changes      *Changes
+	Certificates []Certificate
+	Errors       []CrawlError
+	Thresholds   []Threshold
+}
+
+// Duplicates is a group of Pages whose main text is the same, or nearly the same if not Exact.
+type Duplicates struct {
+	Exact bool
+	URLs  []url.URL
+}
+
+// Changes compares the Pages of a crawl with those of the previous crawl of the same site.
+type Changes struct {
+	Added     []url.URL
+	Changed   []url.URL
+	Unchanged []url.URL
+	Removed   []url.URL
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Threshold is a limit on a problem found by a crawl, such as a CI check of a site's broken links.
+type Threshold struct {
+	Name  string
+	Limit int
+	// Actual is what the crawl measured, to be compared with Limit.
+	Actual int
+	// URLs are the pages with the problem.
+	URLs []url.URL
+	// ExitCode is the status the crawler exits with if the Threshold is exceeded.
+	ExitCode int
+}
+
+// Exceeded reports whether the crawl found more of the problem than the Limit allows.
+func (t Threshold) Exceeded() bool {
+	return t.Actual > t.Limit
+}
+
+// String describes what was measured against the Limit.
+func (t Threshold) String() string {
+	if t.Exceeded() {
+		return fmt.Sprintf("%v is %v, more than the %v allowed", t.Name, t.Actual, t.Limit)
+	}
+
+	return fmt.Sprintf("%v is %v, within the %v allowed", t.Name, t.Actual, t.Limit)
+}
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"crawler/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Errors returned when an Auditor is configured.
+var (
+	ErrUnknownRule     = errors.New("unknown audit rule")
+	ErrInvalidSeverity = errors.New("invalid severity")
+	ErrInvalidLimit    = errors.New("invalid limit, expected 0 or more")
+)
+
+// Names of the rules an Auditor can run.
+const (
+	RuleMissingTitle       = "missing-title"
+	RuleDuplicateTitle     = "duplicate-title"
+	RuleLongTitle          = "long-title"
+	RuleMissingDescription = "missing-description"
+	RuleMultipleH1         = "multiple-h1"
+	RuleCanonicalNotOK     = "canonical-not-ok"
+	RuleTooDeep            = "too-deep"
+	RuleRedirectChain      = "redirect-chain"
+	RuleEmptyAnchor        = "empty-anchor"
+)
+
+// Defaults used for any limit not given in Config.
+const (
+	DefaultMaxTitleLength = 60
+	DefaultMaxDepth       = 3
+	DefaultMaxRedirects   = 1
+)
+
+// Config chooses which rules run, the severity of their findings and how strict they are. A nil limit is the default,
+// and 0 is a valid limit.
+type Config struct {
+	Disabled       []string
+	Severities     map[string]domain.Severity
+	MaxTitleLength *int
+	MaxDepth       *int
+	MaxRedirects   *int
+}
+
+// Auditor runs rules over every page of a crawl.
+type Auditor struct {
+	rules []rule
+}
+
+// rule checks the pages of a crawl, returning a finding for each problem. The Auditor fills in the rule's name and
+// severity.
+type rule struct {
+	name     string
+	severity domain.Severity
+	check    func(pages []domain.Page) []domain.Finding
+}
+
+// New instantiates an Auditor running every rule not disabled by cfg.
+func New(cfg Config) (Auditor, error) {
+	maxTitleLength, err := limit("maxTitleLength", cfg.MaxTitleLength, DefaultMaxTitleLength)
+	if err != nil {
+		return Auditor{}, err
+	}
+
+	maxDepth, err := limit("maxDepth", cfg.MaxDepth, DefaultMaxDepth)
+	if err != nil {
+		return Auditor{}, err
+	}
+
+	maxRedirects, err := limit("maxRedirects", cfg.MaxRedirects, DefaultMaxRedirects)
+	if err != nil {
+		return Auditor{}, err
+	}
+
+	rules := []rule{
+		{name: RuleMissingTitle, severity: domain.SeverityError, check: missingTitle},
+		{name: RuleDuplicateTitle, severity: domain.SeverityWarning, check: duplicateTitle},
+		{name: RuleLongTitle, severity: domain.SeverityWarning, check: longTitle(maxTitleLength)},
+		{name: RuleMissingDescription, severity: domain.SeverityWarning, check: missingDescription},
+		{name: RuleMultipleH1, severity: domain.SeverityInfo, check: multipleH1},
+		{name: RuleCanonicalNotOK, severity: domain.SeverityError, check: canonicalNotOK},
+		{name: RuleTooDeep, severity: domain.SeverityInfo, check: tooDeep(maxDepth)},
+		{name: RuleRedirectChain, severity: domain.SeverityWarning, check: redirectChain(maxRedirects)},
+		{name: RuleEmptyAnchor, severity: domain.SeverityWarning, check: emptyAnchor},
+	}
+
+	known := make(map[string]bool)
+	for _, r := range rules {
+		known[r.name] = true
+	}
+
+	disabled := make(map[string]bool)
+
+	for _, name := range cfg.Disabled {
+		if !known[name] {
+			return Auditor{}, fmt.Errorf("%v: %w", name, ErrUnknownRule)
+		}
+
+		disabled[name] = true
+	}
+
+	for name, severity := range cfg.Severities {
+		if !known[name] {
+			return Auditor{}, fmt.Errorf("%v: %w", name, ErrUnknownRule)
+		}
+
+		if !isSeverity(severity) {
+			return Auditor{}, fmt.Errorf("%v for %v: %w", severity, name, ErrInvalidSeverity)
+		}
+	}
+
+	var enabled []rule
+
+	for _, r := range rules {
+		if disabled[r.name] {
+			continue
+		}
+
+		if severity, ok := cfg.Severities[r.name]; ok {
+			r.severity = severity
+		}
+
+		enabled = append(enabled, r)
+	}
+
+	return Auditor{
+		rules: enabled,
+	}, nil
+}
+
+// Audit runs every rule over the pages, returning the findings ordered by severity, rule and URL.
+func (a Auditor) Audit(pages []domain.Page) []domain.Finding {
+	var findings []domain.Finding
+
+	for _, r := range a.rules {
+		for _, f := range r.check(pages) {
+			f.Rule = r.name
+			f.Severity = r.severity
+
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return rank(findings[i].Severity) < rank(findings[j].Severity)
+		}
+
+		if findings[i].Rule != findings[j].Rule {
+			return findings[i].Rule < findings[j].Rule
+		}
+
+		return findings[i].URL.String() < findings[j].URL.String()
+	})
+
+	return findings
+}
+
+// limit returns the value of the named limit given in Config, or def if it was not given.
+func limit(name string, value *int, def int) (int, error) {
+	if value == nil {
+		return def, nil
+	}
+
+	if *value < 0 {
+		return 0, fmt.Errorf("%v for %v: %w", *value, name, ErrInvalidLimit)
+	}
+
+	return *value, nil
+}
+
+func isSeverity(s domain.Severity) bool {
+	return s == domain.SeverityError || s == domain.SeverityWarning || s == domain.SeverityInfo
+}
+
+// rank orders severities from most to least important.
+func rank(s domain.Severity) int {
+	switch s {
+	case domain.SeverityError:
+		return 0
+	case domain.SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
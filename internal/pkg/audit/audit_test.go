@@ -0,0 +1,265 @@
+package audit
+
+import (
+	"crawler/internal/domain"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestAuditor_Audit(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenConfig      Config
+		givenPages       []domain.Page
+		expectedFindings []domain.Finding
+	}{
+		{
+			name:        "given pages with missing, duplicate and long titles, expect findings for each",
+			givenConfig: Config{Disabled: []string{RuleMissingDescription}, MaxTitleLength: intPointer(10)},
+			givenPages: []domain.Page{
+				htmlPage("/a/", domain.Metadata{}),
+				htmlPage("/b/", domain.Metadata{Title: "Shared"}),
+				htmlPage("/c/", domain.Metadata{Title: "Shared"}),
+				htmlPage("/d/", domain.Metadata{Title: "A title that is too long"}),
+			},
+			expectedFindings: []domain.Finding{
+				{
+					Rule:     RuleMissingTitle,
+					Severity: domain.SeverityError,
+					URL:      pageURL("/a/"),
+					Message:  "page has no title",
+				},
+				{
+					Rule:     RuleDuplicateTitle,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/b/"),
+					Message:  `title "Shared" is shared with 1 other pages`,
+				},
+				{
+					Rule:     RuleDuplicateTitle,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/c/"),
+					Message:  `title "Shared" is shared with 1 other pages`,
+				},
+				{
+					Rule:     RuleLongTitle,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/d/"),
+					Message:  "title is 24 characters, longer than 10",
+				},
+			},
+		},
+		{
+			name: "given a page without a description and with several h1s, expect findings at the configured severity",
+			givenConfig: Config{
+				Severities: map[string]domain.Severity{RuleMultipleH1: domain.SeverityError},
+			},
+			givenPages: []domain.Page{
+				htmlPage("/a/", domain.Metadata{Title: "A", H1: []string{"One", "Two"}}),
+			},
+			expectedFindings: []domain.Finding{
+				{
+					Rule:     RuleMultipleH1,
+					Severity: domain.SeverityError,
+					URL:      pageURL("/a/"),
+					Message:  "page has 2 h1 headings",
+				},
+				{
+					Rule:     RuleMissingDescription,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/a/"),
+					Message:  "page has no meta description",
+				},
+			},
+		},
+		{
+			name: "given canonicals to a missing and a redirected page, expect findings for both",
+			givenPages: []domain.Page{
+				htmlPage("/a/", domain.Metadata{Title: "A", Description: "A", Canonical: pageURL("/missing")}),
+				htmlPage("/b/", domain.Metadata{Title: "B", Description: "B", Canonical: pageURL("/moved/")}),
+				htmlPage("/c/", domain.Metadata{Title: "C", Description: "C", Canonical: pageURL("/c/")}),
+				{URL: pageURL("/missing/"), StatusCode: http.StatusNotFound},
+				{URL: pageURL("/moved/"), StatusCode: http.StatusOK, Redirects: []url.URL{pageURL("/new/")}},
+			},
+			expectedFindings: []domain.Finding{
+				{
+					Rule:     RuleCanonicalNotOK,
+					Severity: domain.SeverityError,
+					URL:      pageURL("/a/"),
+					Message:  "canonical https://example.com/missing responded with 404",
+				},
+				{
+					Rule:     RuleCanonicalNotOK,
+					Severity: domain.SeverityError,
+					URL:      pageURL("/b/"),
+					Message:  "canonical https://example.com/moved/ redirects to https://example.com/new/",
+				},
+			},
+		},
+		{
+			name: "given a canonical to a page which was never fetched, expect no finding",
+			givenPages: []domain.Page{
+				htmlPage("/a/", domain.Metadata{Title: "A", Description: "A", Canonical: pageURL("/queued/")}),
+				{URL: pageURL("/queued/"), Pending: true},
+			},
+		},
+		{
+			name:        "given a deep page and a redirect chain, expect findings for both",
+			givenConfig: Config{MaxDepth: intPointer(2)},
+			givenPages: []domain.Page{
+				{URL: pageURL("/deep/"), Depth: 3},
+				{URL: pageURL("/a/"), Depth: 1, Redirects: []url.URL{pageURL("/b/"), pageURL("/c/")}},
+			},
+			expectedFindings: []domain.Finding{
+				{
+					Rule:     RuleRedirectChain,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/a/"),
+					Message:  "page redirects 2 times: https://example.com/a/ -> https://example.com/b/ -> https://example.com/c/",
+				},
+				{
+					Rule:     RuleTooDeep,
+					Severity: domain.SeverityInfo,
+					URL:      pageURL("/deep/"),
+					Message:  "page is 3 clicks from its seed, more than 2",
+				},
+			},
+		},
+		{
+			name: "given a page with an empty anchor, expect a finding for the link",
+			givenPages: []domain.Page{
+				{
+					URL: pageURL("/a/"),
+					Outlinks: []domain.Link{
+						{URL: pageURL("/b/"), Text: "Click here", Location: domain.LocationBody},
+						{URL: pageURL("/c/"), Location: domain.LocationFooter},
+					},
+				},
+			},
+			expectedFindings: []domain.Finding{
+				{
+					Rule:     RuleEmptyAnchor,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/a/"),
+					Message:  "link to https://example.com/c/ in the footer has no anchor text",
+				},
+			},
+		},
+		{
+			name: "given limits of 0, expect any title, redirect and depth reported",
+			givenConfig: Config{
+				Disabled:       []string{RuleMissingDescription},
+				MaxTitleLength: intPointer(0),
+				MaxDepth:       intPointer(0),
+				MaxRedirects:   intPointer(0),
+			},
+			givenPages: []domain.Page{
+				{URL: pageURL("/a/"), Depth: 1, Redirects: []url.URL{pageURL("/b/")}},
+				htmlPage("/c/", domain.Metadata{Title: "C"}),
+			},
+			expectedFindings: []domain.Finding{
+				{
+					Rule:     RuleLongTitle,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/c/"),
+					Message:  "title is 1 characters, longer than 0",
+				},
+				{
+					Rule:     RuleRedirectChain,
+					Severity: domain.SeverityWarning,
+					URL:      pageURL("/a/"),
+					Message:  "page redirects 1 times: https://example.com/a/ -> https://example.com/b/",
+				},
+				{
+					Rule:     RuleTooDeep,
+					Severity: domain.SeverityInfo,
+					URL:      pageURL("/a/"),
+					Message:  "page is 1 clicks from its seed, more than 0",
+				},
+			},
+		},
+		{
+			name: "given pages which are not HTML, expect no metadata findings",
+			givenPages: []domain.Page{
+				{URL: pageURL("/file.pdf"), StatusCode: http.StatusOK, ContentType: "application/pdf"},
+				{URL: pageURL("/missing/"), StatusCode: http.StatusNotFound, ContentType: "text/html"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			auditor, err := New(test.givenConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := auditor.Audit(test.givenPages)
+
+			if !cmp.Equal(actual, test.expectedFindings) {
+				t.Fatal(cmp.Diff(actual, test.expectedFindings))
+			}
+		})
+	}
+}
+
+func TestNew_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenConfig   Config
+		expectedError error
+	}{
+		{
+			name:          "given an unknown disabled rule, expect error",
+			givenConfig:   Config{Disabled: []string{"missing-titel"}},
+			expectedError: ErrUnknownRule,
+		},
+		{
+			name:          "given a severity for an unknown rule, expect error",
+			givenConfig:   Config{Severities: map[string]domain.Severity{"missing-titel": domain.SeverityError}},
+			expectedError: ErrUnknownRule,
+		},
+		{
+			name:          "given an invalid severity, expect error",
+			givenConfig:   Config{Severities: map[string]domain.Severity{RuleMissingTitle: "fatal"}},
+			expectedError: ErrInvalidSeverity,
+		},
+		{
+			name:          "given a negative limit, expect error",
+			givenConfig:   Config{MaxRedirects: intPointer(-1)},
+			expectedError: ErrInvalidLimit,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.givenConfig)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func htmlPage(path string, metadata domain.Metadata) domain.Page {
+	return domain.Page{
+		URL:         pageURL(path),
+		StatusCode:  http.StatusOK,
+		ContentType: "text/html; charset=utf-8",
+		Metadata:    metadata,
+	}
+}
+
+func pageURL(path string) url.URL {
+	return url.URL{Scheme: "https", Host: "example.com", Path: path}
+}
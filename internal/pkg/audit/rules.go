@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"crawler/internal/domain"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+func missingTitle(pages []domain.Page) []domain.Finding {
+	var findings []domain.Finding
+
+	for _, page := range htmlPages(pages) {
+		if page.Metadata.Title == "" {
+			findings = append(findings, finding(page, "page has no title"))
+		}
+	}
+
+	return findings
+}
+
+func duplicateTitle(pages []domain.Page) []domain.Finding {
+	byTitle := make(map[string][]domain.Page)
+
+	for _, page := range htmlPages(pages) {
+		if page.Metadata.Title != "" {
+			byTitle[page.Metadata.Title] = append(byTitle[page.Metadata.Title], page)
+		}
+	}
+
+	var findings []domain.Finding
+
+	for title, shared := range byTitle {
+		if len(shared) < 2 {
+			continue
+		}
+
+		for _, page := range shared {
+			findings = append(findings, finding(page, fmt.Sprintf("title %q is shared with %v other pages", title, len(shared)-1)))
+		}
+	}
+
+	return findings
+}
+
+func longTitle(maxLength int) func(pages []domain.Page) []domain.Finding {
+	return func(pages []domain.Page) []domain.Finding {
+		var findings []domain.Finding
+
+		for _, page := range htmlPages(pages) {
+			length := utf8.RuneCountInString(page.Metadata.Title)
+			if length > maxLength {
+				findings = append(findings, finding(page, fmt.Sprintf("title is %v characters, longer than %v", length, maxLength)))
+			}
+		}
+
+		return findings
+	}
+}
+
+func missingDescription(pages []domain.Page) []domain.Finding {
+	var findings []domain.Finding
+
+	for _, page := range htmlPages(pages) {
+		if page.Metadata.Description == "" {
+			findings = append(findings, finding(page, "page has no meta description"))
+		}
+	}
+
+	return findings
+}
+
+func multipleH1(pages []domain.Page) []domain.Finding {
+	var findings []domain.Finding
+
+	for _, page := range htmlPages(pages) {
+		if len(page.Metadata.H1) > 1 {
+			findings = append(findings, finding(page, fmt.Sprintf("page has %v h1 headings", len(page.Metadata.H1))))
+		}
+	}
+
+	return findings
+}
+
+// canonicalNotOK finds canonical links to crawled pages which did not respond with a 200, or only did so after a
+// redirect. Canonical links to pages which were not crawled are not checked.
+func canonicalNotOK(pages []domain.Page) []domain.Finding {
+	byURL := make(map[url.URL]domain.Page)
+	for _, page := range pages {
+		byURL[page.URL] = page
+	}
+
+	var findings []domain.Finding
+
+	for _, page := range htmlPages(pages) {
+		canonical := page.Metadata.Canonical
+		if canonical == (url.URL{}) {
+			continue
+		}
+
+		// A Pending target was queued but never fetched, so how it responds is not known.
+		target, ok := lookup(byURL, canonical)
+		if !ok || target.Pending {
+			continue
+		}
+
+		switch {
+		case target.StatusCode != http.StatusOK:
+			findings = append(findings, finding(page, fmt.Sprintf("canonical %v responded with %v", canonical.String(), status(target))))
+		case len(target.Redirects) > 0:
+			final := target.Redirects[len(target.Redirects)-1]
+			findings = append(findings, finding(page, fmt.Sprintf("canonical %v redirects to %v", canonical.String(), final.String())))
+		}
+	}
+
+	return findings
+}
+
+func tooDeep(maxDepth int) func(pages []domain.Page) []domain.Finding {
+	return func(pages []domain.Page) []domain.Finding {
+		var findings []domain.Finding
+
+		for _, page := range pages {
+			if page.Depth > maxDepth {
+				findings = append(findings, finding(page, fmt.Sprintf("page is %v clicks from its seed, more than %v", page.Depth, maxDepth)))
+			}
+		}
+
+		return findings
+	}
+}
+
+func redirectChain(maxRedirects int) func(pages []domain.Page) []domain.Finding {
+	return func(pages []domain.Page) []domain.Finding {
+		var findings []domain.Finding
+
+		for _, page := range pages {
+			if len(page.Redirects) <= maxRedirects {
+				continue
+			}
+
+			hops := []string{page.URL.String()}
+			for _, u := range page.Redirects {
+				hops = append(hops, u.String())
+			}
+
+			findings = append(findings, finding(page, fmt.Sprintf("page redirects %v times: %v", len(page.Redirects), strings.Join(hops, " -> "))))
+		}
+
+		return findings
+	}
+}
+
+// emptyAnchor finds links with no text, aria-label or image alt text to describe where they go.
+func emptyAnchor(pages []domain.Page) []domain.Finding {
+	var findings []domain.Finding
+
+	for _, page := range pages {
+		for _, link := range page.Outlinks {
+			if link.Text == "" {
+				findings = append(findings, finding(page, fmt.Sprintf("link to %v in the %v has no anchor text", link.URL.String(), link.Location)))
+			}
+		}
+	}
+
+	return findings
+}
+
+// htmlPages returns the pages which responded with HTML that was parsed, as only they have metadata.
+func htmlPages(pages []domain.Page) []domain.Page {
+	var found []domain.Page
+
+	for _, page := range pages {
+		if page.StatusCode != http.StatusOK || (page.SkipReason != "" && !page.Truncated) {
+			continue
+		}
+
+		mediaType, _, err := mime.ParseMediaType(page.ContentType)
+		if err != nil {
+			continue
+		}
+
+		if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+			found = append(found, page)
+		}
+	}
+
+	return found
+}
+
+// lookup finds the page for a URL, ignoring its fragment and whether its path has a trailing slash, as crawled URLs
+// are cleaned before they are fetched.
+func lookup(byURL map[url.URL]domain.Page, u url.URL) (domain.Page, bool) {
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if page, ok := byURL[u]; ok {
+		return page, true
+	}
+
+	if strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+	} else {
+		u.Path += "/"
+	}
+
+	page, ok := byURL[u]
+
+	return page, ok
+}
+
+// status describes the response of a page, which has no status code if it could not be fetched at all.
+func status(page domain.Page) string {
+	if page.StatusCode == 0 {
+		return "no response"
+	}
+
+	return fmt.Sprint(page.StatusCode)
+}
+
+func finding(page domain.Page, message string) domain.Finding {
+	return domain.Finding{
+		URL:     page.URL,
+		Message: message,
+	}
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"net/http"
+)
+
+// Basic authenticates requests with HTTP Basic credentials.
+type Basic struct {
+	username string
+	password string
+}
+
+// NewBasic instantiates a Basic authenticator.
+func NewBasic(username, password string) Basic {
+	return Basic{
+		username: username,
+		password: password,
+	}
+}
+
+// Authenticate sets the Authorization header of the http.Request.
+func (b Basic) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(b.username, b.password)
+
+	return nil
+}
+
+// Bearer authenticates requests with a static bearer token.
+type Bearer struct {
+	token string
+}
+
+// NewBearer instantiates a Bearer authenticator.
+func NewBearer(token string) Bearer {
+	return Bearer{
+		token: token,
+	}
+}
+
+// Authenticate sets the Authorization header of the http.Request.
+func (b Bearer) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.token)
+
+	return nil
+}
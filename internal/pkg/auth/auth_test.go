@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crawler/internal/pkg/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name                  string
+		givenAuthenticator    httpclient.Authenticator
+		expectedAuthorization string
+	}{
+		{
+			name:                  "given basic credentials, expect a basic Authorization header",
+			givenAuthenticator:    NewBasic("user", "pass"),
+			expectedAuthorization: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:                  "given a bearer token, expect a bearer Authorization header",
+			givenAuthenticator:    NewBearer("token"),
+			expectedAuthorization: "Bearer token",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://example.com", nil)
+
+			err := test.givenAuthenticator.Authenticate(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := req.Header.Get("Authorization")
+
+			if !cmp.Equal(actual, test.expectedAuthorization) {
+				t.Fatal(cmp.Diff(actual, test.expectedAuthorization))
+			}
+		})
+	}
+}
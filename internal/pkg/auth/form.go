@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crawler/internal/pkg/httpclient"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrFailedToLogin is returned if the login form could not be submitted or was rejected.
+var (
+	ErrFailedToLogin = errors.New("failed to log in")
+)
+
+// Form authenticates by submitting a login form before the first request it authenticates, and again before later
+// requests until a login succeeds. The session cookies the login responds with are kept by the cookie jar of the Doer,
+// which sends them with later requests.
+type Form struct {
+	doer      httpclient.Doer
+	requester httpclient.Requester
+	loginURL  url.URL
+	fields    url.Values
+
+	loggedIn bool
+	mu       sync.Mutex
+}
+
+// NewForm instantiates a Form which POSTs fields to loginURL. The Doer must have a cookie jar.
+func NewForm(doer httpclient.Doer, requester httpclient.Requester, loginURL url.URL, fields url.Values) *Form {
+	return &Form{
+		doer:      doer,
+		requester: requester,
+		loginURL:  loginURL,
+		fields:    fields,
+	}
+}
+
+// Authenticate logs in if it has not already, returning the error of the login if it failed.
+func (f *Form) Authenticate(req *http.Request) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.loggedIn {
+		return nil
+	}
+
+	err := f.login(req)
+	if err != nil {
+		return err
+	}
+
+	f.loggedIn = true
+
+	return nil
+}
+
+func (f *Form) login(req *http.Request) error {
+	login, err := f.requester.
+		WithMethod(http.MethodPost).
+		WithURL(f.loginURL).
+		WithHeader("Content-Type", "application/x-www-form-urlencoded").
+		WithBody(strings.NewReader(f.fields.Encode())).
+		Build(req.Context())
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrFailedToLogin)
+	}
+
+	res, err := f.doer.Do(login)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrFailedToLogin)
+	}
+
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%v responded with %v: %w", f.loginURL.String(), res.StatusCode, ErrFailedToLogin)
+	}
+
+	return nil
+}
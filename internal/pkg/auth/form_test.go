@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crawler/internal/pkg/requester"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestForm_Authenticate_Success(t *testing.T) {
+	logins := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			logins++
+
+			if r.Method != http.MethodPost || r.PostFormValue("username") != "user" || r.PostFormValue("password") != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		default:
+			if c, err := r.Cookie("session"); err != nil || c.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Jar: jar}
+	loginURL, _ := url.Parse(server.URL + "/login")
+
+	form := NewForm(client, requester.New(), *loginURL, url.Values{"username": {"user"}, "password": {"pass"}})
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, server.URL+"/private", nil)
+		req.RequestURI = ""
+
+		err = form.Authenticate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if !cmp.Equal(res.StatusCode, http.StatusOK) {
+			t.Fatal(cmp.Diff(res.StatusCode, http.StatusOK))
+		}
+	}
+
+	if !cmp.Equal(logins, 1) {
+		t.Fatal(cmp.Diff(logins, 1))
+	}
+}
+
+func TestForm_Authenticate_Retry(t *testing.T) {
+	logins := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logins++
+
+		if logins == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	loginURL, _ := url.Parse(server.URL + "/login")
+	form := NewForm(server.Client(), requester.New(), *loginURL, url.Values{"username": {"user"}, "password": {"pass"}})
+
+	err := form.Authenticate(httptest.NewRequest(http.MethodGet, server.URL, nil))
+	if !cmp.Equal(err, ErrFailedToLogin, cmpopts.EquateErrors()) {
+		t.Fatal(cmp.Diff(err, ErrFailedToLogin, cmpopts.EquateErrors()))
+	}
+
+	for i := 0; i < 2; i++ {
+		err = form.Authenticate(httptest.NewRequest(http.MethodGet, server.URL, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !cmp.Equal(logins, 2) {
+		t.Fatal(cmp.Diff(logins, 2))
+	}
+}
+
+func TestForm_Authenticate_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenFields   url.Values
+		expectedError error
+	}{
+		{
+			name:          "given rejected credentials, expect error",
+			givenFields:   url.Values{"username": {"user"}, "password": {"wrong"}},
+			expectedError: ErrFailedToLogin,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.PostFormValue("password") != "pass" {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			}))
+			defer server.Close()
+
+			loginURL, _ := url.Parse(server.URL + "/login")
+			form := NewForm(server.Client(), requester.New(), *loginURL, test.givenFields)
+
+			err := form.Authenticate(httptest.NewRequest(http.MethodGet, server.URL, nil))
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cssparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+)
+
+// ErrFailedToParseHTML is returned if the body cannot be parsed as HTML.
+var (
+	ErrFailedToParseHTML = errors.New("failed to parse html")
+)
+
+// HTMLParser finds all URLs referenced by the CSS within a HTML document.
+type HTMLParser struct {
+	URLBuilder URLBuilder
+}
+
+// NewHTML instantiates a HTMLParser.
+func NewHTML(builder URLBuilder) HTMLParser {
+	return HTMLParser{
+		URLBuilder: builder,
+	}
+}
+
+// FetchLinks finds the linked stylesheets of a HTML document, along with the URLs referenced by its <style> blocks
+// and style attributes, resolved relative to the document's URL.
+func (h HTMLParser) FetchLinks(ctx context.Context, body io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+	doc, err := htmlquery.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseHTML)
+	}
+
+	var refs []string
+
+	for _, link := range htmlquery.Find(doc, "//link[@href]") {
+		if isStylesheet(htmlquery.SelectAttr(link, "rel")) {
+			refs = append(refs, strings.TrimSpace(htmlquery.SelectAttr(link, "href")))
+		}
+	}
+
+	for _, style := range htmlquery.Find(doc, "//style") {
+		refs = append(refs, References(htmlquery.InnerText(style))...)
+	}
+
+	for _, attr := range htmlquery.Find(doc, "//@style") {
+		refs = append(refs, References(htmlquery.InnerText(attr))...)
+	}
+
+	span := urlbuilder.StartSpan(ctx, "URLBuilder.Clean")
+	defer span.End()
+
+	return h.URLBuilder.Clean(toURLs(refs), baseURL), nil
+}
+
+// isStylesheet reports whether a rel attribute, which may hold several space separated values, is a stylesheet.
+func isStylesheet(rel string) bool {
+	for _, value := range strings.Fields(rel) {
+		if strings.EqualFold(value, "stylesheet") {
+			return true
+		}
+	}
+
+	return false
+}
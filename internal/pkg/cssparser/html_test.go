@@ -0,0 +1,53 @@
+package cssparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHTMLParser_FetchLinks_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenHTML    io.Reader
+		givenURL     *url.URL
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given stylesheets, style blocks and style attributes, expect their references resolved to the page",
+			givenHTML: strings.NewReader(`<html><head>
+				<link rel="stylesheet" href="css/site.css"><link rel="icon" href="/favicon.ico">
+				<style>@import "print.css"; h1 { background: url(/img/h1.png) }</style></head>
+				<body><div style="background-image: url('hero.jpg')">Hi</div></body></html>`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com", Path: "/blog/"},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/blog/css/site.css"},
+				{Scheme: "https", Host: "example.com", Path: "/blog/hero.jpg"},
+				{Scheme: "https", Host: "example.com", Path: "/blog/print.css"},
+				{Scheme: "https", Host: "example.com", Path: "/img/h1.png"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := NewHTML(urlbuilder.New()).FetchLinks(context.Background(), test.givenHTML, test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Path < actual[j].Path
+			})
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
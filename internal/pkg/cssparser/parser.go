@@ -0,0 +1,88 @@
+package cssparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrFailedToReadCSS is returned if the body cannot be read.
+var (
+	ErrFailedToReadCSS = errors.New("failed to read css")
+)
+
+// urlFunction matches the argument of a CSS url() function, quoted or not.
+var urlFunction = regexp.MustCompile(`url\(\s*['"]?([^'")]*?)['"]?\s*\)`)
+
+// importString matches an @import of a quoted URL. An @import of a url() is matched by urlFunction.
+var importString = regexp.MustCompile(`@import\s+['"]([^'"]+)['"]`)
+
+// CSSParser finds all URLs referenced by a stylesheet.
+type CSSParser struct {
+	URLBuilder URLBuilder
+}
+
+// New instantiates a CSSParser.
+func New(builder URLBuilder) CSSParser {
+	return CSSParser{
+		URLBuilder: builder,
+	}
+}
+
+// URLBuilder removes the found url.URLs that should not be crawled and normalises the rest.
+type URLBuilder interface {
+	Clean([]*url.URL, *url.URL) []*url.URL
+}
+
+// FetchLinks finds the URL of every url() function and @import within the stylesheet, resolved relative to the
+// stylesheet's URL.
+func (c CSSParser) FetchLinks(ctx context.Context, body io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToReadCSS)
+	}
+
+	span := urlbuilder.StartSpan(ctx, "URLBuilder.Clean")
+	defer span.End()
+
+	return c.URLBuilder.Clean(toURLs(References(string(b))), baseURL), nil
+}
+
+// References returns the raw URL of every url() function and @import within the CSS, ignoring inline data URIs.
+func References(css string) []string {
+	var refs []string
+
+	for _, pattern := range []*regexp.Regexp{urlFunction, importString} {
+		for _, match := range pattern.FindAllStringSubmatch(css, -1) {
+			ref := strings.TrimSpace(match[1])
+			if ref == "" || strings.HasPrefix(strings.ToLower(ref), "data:") {
+				continue
+			}
+
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+// toURLs parses the raw references, skipping any which are not valid URLs.
+func toURLs(refs []string) []*url.URL {
+	var found []*url.URL
+
+	for _, ref := range refs {
+		u, err := url.Parse(ref)
+		if err != nil {
+			continue
+		}
+
+		found = append(found, u)
+	}
+
+	return found
+}
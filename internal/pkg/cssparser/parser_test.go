@@ -0,0 +1,61 @@
+package cssparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCSSParser_FetchLinks_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenCSS     io.Reader
+		givenURL     *url.URL
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given @imports within a nested stylesheet, expect them resolved relative to the stylesheet",
+			givenCSS: strings.NewReader(`@import "reset.css"; @import url(../fonts/fonts.css) screen;
+				@import 'https://example.org/remote.css';`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com", Path: "/assets/css/site.css"},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/assets/css/reset.css"},
+				{Scheme: "https", Host: "example.com", Path: "/assets/fonts/fonts.css"},
+			},
+		},
+		{
+			name: "given quoted and unquoted url() functions, expect their URLs returned without data URIs",
+			givenCSS: strings.NewReader(`body { background: url( "/img/bg.png" ); }
+				@font-face { src: url('https://example.com/fonts/a.woff2') format("woff2"), url(/fonts/a.woff); }
+				.icon { background: url(data:image/png;base64,iVBORw0KGgo=); }`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com"},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/fonts/a.woff"},
+				{Scheme: "https", Host: "example.com", Path: "/fonts/a.woff2"},
+				{Scheme: "https", Host: "example.com", Path: "/img/bg.png"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(urlbuilder.New()).FetchLinks(context.Background(), test.givenCSS, test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Path < actual[j].Path
+			})
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"crawler/internal/domain"
+	"net/url"
+	"sort"
+)
+
+// Compare finds what changed between the pages of an old and a current crawl. Pending pages of the current crawl were
+// not fetched, so they are only used to tell the pages which were removed.
+func Compare(old, current []domain.Page) domain.Diff {
+	before := make(map[url.URL]domain.Page)
+	for _, page := range old {
+		before[page.URL] = page
+	}
+
+	after := make(map[url.URL]bool)
+
+	var d domain.Diff
+
+	for _, page := range current {
+		after[page.URL] = true
+
+		if page.Pending {
+			continue
+		}
+
+		previous, ok := before[page.URL]
+		comparePage(&d, page, previous, ok)
+	}
+
+	for _, page := range old {
+		if !after[page.URL] {
+			d.Removed = append(d.Removed, page.URL)
+		}
+	}
+
+	sortDiff(&d)
+
+	return d
+}
+
+// comparePage adds what changed about a page to d. The previous page is only compared if ok, and it was fetched.
+func comparePage(d *domain.Diff, page, previous domain.Page, ok bool) {
+	if isBroken(page) && (!ok || !isBroken(previous)) {
+		d.BrokenLinks = append(d.BrokenLinks, domain.BrokenLink{
+			URL:        page.URL,
+			Referrer:   page.Referrer,
+			StatusCode: page.StatusCode,
+		})
+	}
+
+	if target, redirected := finalURL(page); redirected {
+		if previousTarget, _ := finalURL(previous); !ok || previousTarget != target {
+			d.Redirects = append(d.Redirects, domain.Redirect{URL: page.URL, Target: target})
+		}
+	}
+
+	if !ok {
+		d.Added = append(d.Added, page.URL)
+
+		return
+	}
+
+	if previous.Pending {
+		return
+	}
+
+	if page.StatusCode != previous.StatusCode {
+		d.StatusChanges = append(d.StatusChanges, domain.StatusChange{
+			URL: page.URL,
+			Old: previous.StatusCode,
+			New: page.StatusCode,
+		})
+	}
+
+	// Pages which were not fetched again have no metadata to compare.
+	if !page.Unchanged {
+		compareMetadata(d, page, previous)
+	}
+}
+
+// compareMetadata adds the changes to the title and canonical of a page to d.
+func compareMetadata(d *domain.Diff, page, previous domain.Page) {
+	if page.Metadata.Title != previous.Metadata.Title {
+		d.TitleChanges = append(d.TitleChanges, domain.Change{
+			URL: page.URL,
+			Old: previous.Metadata.Title,
+			New: page.Metadata.Title,
+		})
+	}
+
+	if page.Metadata.Canonical != previous.Metadata.Canonical {
+		d.CanonicalChanges = append(d.CanonicalChanges, domain.Change{
+			URL: page.URL,
+			Old: canonical(previous),
+			New: canonical(page),
+		})
+	}
+}
+
+// isBroken reports whether a page could not be fetched or responded with a client or server error. A Pending page was
+// never requested, so it is not broken.
+func isBroken(page domain.Page) bool {
+	return !page.Pending && (page.StatusCode == 0 || page.StatusCode >= 400)
+}
+
+// finalURL returns where a page was redirected to, if it was.
+func finalURL(page domain.Page) (url.URL, bool) {
+	if len(page.Redirects) == 0 {
+		return url.URL{}, false
+	}
+
+	return page.Redirects[len(page.Redirects)-1], true
+}
+
+func canonical(page domain.Page) string {
+	if page.Metadata.Canonical == (url.URL{}) {
+		return ""
+	}
+
+	return page.Metadata.Canonical.String()
+}
+
+func sortDiff(d *domain.Diff) {
+	sortURLs(d.Added)
+	sortURLs(d.Removed)
+
+	sort.Slice(d.StatusChanges, func(i, j int) bool {
+		return d.StatusChanges[i].URL.String() < d.StatusChanges[j].URL.String()
+	})
+
+	sort.Slice(d.BrokenLinks, func(i, j int) bool {
+		return d.BrokenLinks[i].URL.String() < d.BrokenLinks[j].URL.String()
+	})
+
+	sort.Slice(d.TitleChanges, func(i, j int) bool {
+		return d.TitleChanges[i].URL.String() < d.TitleChanges[j].URL.String()
+	})
+
+	sort.Slice(d.CanonicalChanges, func(i, j int) bool {
+		return d.CanonicalChanges[i].URL.String() < d.CanonicalChanges[j].URL.String()
+	})
+
+	sort.Slice(d.Redirects, func(i, j int) bool {
+		return d.Redirects[i].URL.String() < d.Redirects[j].URL.String()
+	})
+}
+
+func sortURLs(urls []url.URL) {
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].String() < urls[j].String()
+	})
+}
@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"crawler/internal/domain"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenOld     []domain.Page
+		givenCurrent []domain.Page
+		expectedDiff domain.Diff
+	}{
+		{
+			name: "given pages added and removed, expect both reported",
+			givenOld: []domain.Page{
+				{URL: pageURL("/"), StatusCode: http.StatusOK},
+				{URL: pageURL("/gone/"), StatusCode: http.StatusOK},
+			},
+			givenCurrent: []domain.Page{
+				{URL: pageURL("/"), StatusCode: http.StatusOK},
+				{URL: pageURL("/new/"), StatusCode: http.StatusOK},
+			},
+			expectedDiff: domain.Diff{
+				Added:   []url.URL{pageURL("/new/")},
+				Removed: []url.URL{pageURL("/gone/")},
+			},
+		},
+		{
+			name: "given pages which broke, expect status changes and broken links not broken before",
+			givenOld: []domain.Page{
+				{URL: pageURL("/a/"), StatusCode: http.StatusOK},
+				{URL: pageURL("/b/"), StatusCode: http.StatusNotFound},
+			},
+			givenCurrent: []domain.Page{
+				{URL: pageURL("/a/"), Referrer: pageURL("/"), StatusCode: http.StatusInternalServerError},
+				{URL: pageURL("/b/"), Referrer: pageURL("/"), StatusCode: http.StatusNotFound},
+				{URL: pageURL("/c/"), Referrer: pageURL("/")},
+			},
+			expectedDiff: domain.Diff{
+				Added: []url.URL{pageURL("/c/")},
+				StatusChanges: []domain.StatusChange{
+					{URL: pageURL("/a/"), Old: http.StatusOK, New: http.StatusInternalServerError},
+				},
+				BrokenLinks: []domain.BrokenLink{
+					{URL: pageURL("/a/"), Referrer: pageURL("/"), StatusCode: http.StatusInternalServerError},
+					{URL: pageURL("/c/"), Referrer: pageURL("/")},
+				},
+			},
+		},
+		{
+			name: "given changed titles, canonicals and redirects, expect each reported",
+			givenOld: []domain.Page{
+				{URL: pageURL("/a/"), Metadata: domain.Metadata{Title: "Old", Canonical: pageURL("/a/")}},
+				{URL: pageURL("/b/"), Redirects: []url.URL{pageURL("/c/")}},
+				{URL: pageURL("/d/"), Redirects: []url.URL{pageURL("/e/")}},
+				{URL: pageURL("/f/"), Metadata: domain.Metadata{Title: "Same"}},
+			},
+			givenCurrent: []domain.Page{
+				{URL: pageURL("/a/"), Metadata: domain.Metadata{Title: "New"}, Redirects: []url.URL{pageURL("/z/")}},
+				{URL: pageURL("/b/"), Redirects: []url.URL{pageURL("/c/")}},
+				{URL: pageURL("/d/"), Redirects: []url.URL{pageURL("/f/")}},
+				{URL: pageURL("/f/"), Unchanged: true},
+			},
+			expectedDiff: domain.Diff{
+				TitleChanges: []domain.Change{
+					{URL: pageURL("/a/"), Old: "Old", New: "New"},
+				},
+				CanonicalChanges: []domain.Change{
+					{URL: pageURL("/a/"), Old: "https://example.com/a/"},
+				},
+				Redirects: []domain.Redirect{
+					{URL: pageURL("/a/"), Target: pageURL("/z/")},
+					{URL: pageURL("/d/"), Target: pageURL("/f/")},
+				},
+			},
+		},
+		{
+			name: "given pages of an interrupted crawl, expect pending pages to be neither broken nor changed",
+			givenOld: []domain.Page{
+				{URL: pageURL("/a/"), StatusCode: http.StatusOK, Metadata: domain.Metadata{Title: "A"}},
+				{URL: pageURL("/b/"), Pending: true},
+				{URL: pageURL("/gone/"), StatusCode: http.StatusOK},
+			},
+			givenCurrent: []domain.Page{
+				{URL: pageURL("/a/"), Referrer: pageURL("/"), Pending: true},
+				{URL: pageURL("/b/"), Referrer: pageURL("/"), StatusCode: http.StatusOK, Metadata: domain.Metadata{Title: "B"}},
+				{URL: pageURL("/c/"), Referrer: pageURL("/"), Pending: true},
+			},
+			expectedDiff: domain.Diff{
+				Removed: []url.URL{pageURL("/gone/")},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Compare(test.givenOld, test.givenCurrent)
+
+			if !cmp.Equal(actual, test.expectedDiff) {
+				t.Fatal(cmp.Diff(actual, test.expectedDiff))
+			}
+		})
+	}
+}
+
+func pageURL(path string) url.URL {
+	return url.URL{Scheme: "https", Host: "example.com", Path: path}
+}
@@ -0,0 +1,227 @@
+package diff
+
+import (
+	"crawler/api"
+	"crawler/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Errors returned when a domain.Diff is formatted.
+var (
+	ErrUnknownFormat   = errors.New("unknown diff format")
+	ErrFailedToMarshal = errors.New("failed to marshal diff")
+)
+
+// Format is how a domain.Diff is written out.
+type Format string
+
+var (
+	Text     Format = "text"
+	JSON     Format = "json"
+	Markdown Format = "markdown"
+)
+
+// section is a titled list of the lines describing one kind of change.
+type section struct {
+	title string
+	lines []string
+}
+
+// Print writes the domain.Diff in the given Format.
+func Print(d domain.Diff, format Format) (string, error) {
+	switch format {
+	case Text:
+		return text(sections(d)), nil
+	case Markdown:
+		return markdown(sections(d)), nil
+	case JSON:
+		b, err := json.MarshalIndent(adaptPresentationDiffFromDomain(d), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("%v: %w", err, ErrFailedToMarshal)
+		}
+
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("%v: %w", format, ErrUnknownFormat)
+	}
+}
+
+func sections(d domain.Diff) []section {
+	added := section{title: "Added"}
+	for _, u := range d.Added {
+		added.lines = append(added.lines, u.String())
+	}
+
+	removed := section{title: "Removed"}
+	for _, u := range d.Removed {
+		removed.lines = append(removed.lines, u.String())
+	}
+
+	statuses := section{title: "Status changes"}
+	for _, c := range d.StatusChanges {
+		statuses.lines = append(statuses.lines, fmt.Sprintf("%v: %v -> %v", c.URL.String(), status(c.Old), status(c.New)))
+	}
+
+	broken := section{title: "New broken links"}
+	for _, l := range d.BrokenLinks {
+		line := fmt.Sprintf("%v (%v)", l.URL.String(), status(l.StatusCode))
+		if l.Referrer != (url.URL{}) {
+			line += fmt.Sprintf(" linked from %v", l.Referrer.String())
+		}
+
+		broken.lines = append(broken.lines, line)
+	}
+
+	titles := section{title: "Title changes"}
+	for _, c := range d.TitleChanges {
+		titles.lines = append(titles.lines, fmt.Sprintf("%v: %q -> %q", c.URL.String(), c.Old, c.New))
+	}
+
+	canonicals := section{title: "Canonical changes"}
+	for _, c := range d.CanonicalChanges {
+		canonicals.lines = append(canonicals.lines, fmt.Sprintf("%v: %q -> %q", c.URL.String(), c.Old, c.New))
+	}
+
+	redirects := section{title: "New redirects"}
+	for _, r := range d.Redirects {
+		redirects.lines = append(redirects.lines, fmt.Sprintf("%v -> %v", r.URL.String(), r.Target.String()))
+	}
+
+	return []section{added, removed, statuses, broken, titles, canonicals, redirects}
+}
+
+// text lists each section with changes, indenting its lines under the title.
+func text(sections []section) string {
+	var b strings.Builder
+
+	for _, s := range sections {
+		if len(s.lines) == 0 {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%v (%v)\n", s.title, len(s.lines))
+
+		for _, line := range s.lines {
+			fmt.Fprintf(&b, "  %v\n", line)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "No changes\n"
+	}
+
+	return b.String()
+}
+
+// markdown lists each section with changes under a heading.
+func markdown(sections []section) string {
+	var b strings.Builder
+
+	b.WriteString("# Crawl diff\n")
+
+	changed := false
+
+	for _, s := range sections {
+		if len(s.lines) == 0 {
+			continue
+		}
+
+		changed = true
+
+		fmt.Fprintf(&b, "\n## %v (%v)\n\n", s.title, len(s.lines))
+
+		for _, line := range s.lines {
+			fmt.Fprintf(&b, "- %v\n", line)
+		}
+	}
+
+	if !changed {
+		b.WriteString("\nNo changes\n")
+	}
+
+	return b.String()
+}
+
+// status describes a status code, which is 0 if the page could not be fetched at all.
+func status(code int) string {
+	if code == 0 {
+		return "no response"
+	}
+
+	return fmt.Sprint(code)
+}
+
+func adaptPresentationDiffFromDomain(d domain.Diff) api.Diff {
+	presentation := api.Diff{
+		Added:            adaptPresentationURLsFromDomain(d.Added),
+		Removed:          adaptPresentationURLsFromDomain(d.Removed),
+		StatusChanges:    make([]api.StatusChange, len(d.StatusChanges)),
+		BrokenLinks:      make([]api.BrokenLink, len(d.BrokenLinks)),
+		TitleChanges:     adaptPresentationChangesFromDomain(d.TitleChanges),
+		CanonicalChanges: adaptPresentationChangesFromDomain(d.CanonicalChanges),
+		Redirects:        make([]api.Redirect, len(d.Redirects)),
+	}
+
+	for i, c := range d.StatusChanges {
+		presentation.StatusChanges[i] = api.StatusChange{URL: adaptPresentationURLFromDomain(c.URL), Old: c.Old, New: c.New}
+	}
+
+	for i, l := range d.BrokenLinks {
+		presentation.BrokenLinks[i] = api.BrokenLink{
+			URL:        adaptPresentationURLFromDomain(l.URL),
+			Referrer:   adaptPresentationURLFromDomain(l.Referrer),
+			StatusCode: l.StatusCode,
+		}
+	}
+
+	for i, r := range d.Redirects {
+		presentation.Redirects[i] = api.Redirect{
+			URL:    adaptPresentationURLFromDomain(r.URL),
+			Target: adaptPresentationURLFromDomain(r.Target),
+		}
+	}
+
+	return presentation
+}
+
+func adaptPresentationChangesFromDomain(changes []domain.Change) []api.Change {
+	presentation := make([]api.Change, len(changes))
+
+	for i, c := range changes {
+		presentation[i] = api.Change{URL: adaptPresentationURLFromDomain(c.URL), Old: c.Old, New: c.New}
+	}
+
+	return presentation
+}
+
+func adaptPresentationURLsFromDomain(urls []url.URL) []api.URL {
+	presentation := make([]api.URL, len(urls))
+
+	for i := range urls {
+		presentation[i] = adaptPresentationURLFromDomain(urls[i])
+	}
+
+	return presentation
+}
+
+func adaptPresentationURLFromDomain(u url.URL) api.URL {
+	return api.URL{
+		Scheme:      u.Scheme,
+		Opaque:      u.Opaque,
+		Host:        u.Host,
+		Path:        u.Path,
+		RawPath:     u.RawPath,
+		ForceQuery:  u.ForceQuery,
+		RawQuery:    u.RawQuery,
+		Fragment:    u.Fragment,
+		RawFragment: u.RawFragment,
+	}
+}
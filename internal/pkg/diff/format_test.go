@@ -0,0 +1,156 @@
+package diff
+
+import (
+	"crawler/internal/domain"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPrint_Success(t *testing.T) {
+	givenDiff := domain.Diff{
+		Added: []url.URL{pageURL("/new/")},
+		StatusChanges: []domain.StatusChange{
+			{URL: pageURL("/a/"), Old: http.StatusOK, New: http.StatusNotFound},
+		},
+		BrokenLinks: []domain.BrokenLink{
+			{URL: pageURL("/a/"), Referrer: pageURL("/"), StatusCode: http.StatusNotFound},
+		},
+		TitleChanges: []domain.Change{
+			{URL: pageURL("/b/"), Old: "Old", New: "New"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		given    domain.Diff
+		format   Format
+		expected string
+	}{
+		{
+			name:   "given a diff, expect text output",
+			given:  givenDiff,
+			format: Text,
+			expected: `Added (1)
+  https://example.com/new/
+
+Status changes (1)
+  https://example.com/a/: 200 -> 404
+
+New broken links (1)
+  https://example.com/a/ (404) linked from https://example.com/
+
+Title changes (1)
+  https://example.com/b/: "Old" -> "New"
+`,
+		},
+		{
+			name:   "given a diff, expect markdown output",
+			given:  givenDiff,
+			format: Markdown,
+			expected: `# Crawl diff
+
+## Added (1)
+
+- https://example.com/new/
+
+## Status changes (1)
+
+- https://example.com/a/: 200 -> 404
+
+## New broken links (1)
+
+- https://example.com/a/ (404) linked from https://example.com/
+
+## Title changes (1)
+
+- https://example.com/b/: "Old" -> "New"
+`,
+		},
+		{
+			name:     "given no changes, expect text saying so",
+			format:   Text,
+			expected: "No changes\n",
+		},
+		{
+			name:   "given a redirect, expect JSON output",
+			given:  domain.Diff{Redirects: []domain.Redirect{{URL: url.URL{Host: "a.com"}, Target: url.URL{Host: "b.com"}}}},
+			format: JSON,
+			expected: `{
+  "added": [],
+  "removed": [],
+  "statusChanges": [],
+  "brokenLinks": [],
+  "titleChanges": [],
+  "canonicalChanges": [],
+  "redirects": [
+    {
+      "url": {
+        "scheme": "",
+        "opaque": "",
+        "host": "a.com",
+        "path": "",
+        "rawPath": "",
+        "forceQuery": false,
+        "rawQuery": "",
+        "fragment": "",
+        "rawFragment": ""
+      },
+      "target": {
+        "scheme": "",
+        "opaque": "",
+        "host": "b.com",
+        "path": "",
+        "rawPath": "",
+        "forceQuery": false,
+        "rawQuery": "",
+        "fragment": "",
+        "rawFragment": ""
+      }
+    }
+  ]
+}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Print(test.given, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual != test.expected {
+				t.Fatal(cmp.Diff(actual, test.expected))
+			}
+		})
+	}
+}
+
+func TestPrint_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        Format
+		expectedError error
+	}{
+		{
+			name:          "given an unknown format, expect error",
+			format:        "html",
+			expectedError: ErrUnknownFormat,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Print(domain.Diff{}, test.format)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrNoAddresses is returned if a host resolves to no addresses.
+var (
+	ErrNoAddresses = errors.New("host has no addresses")
+)
+
+// LookupProvider resolves a host to its IP addresses.
+type LookupProvider interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DialProvider opens a connection to an address.
+type DialProvider interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Resolver dials hosts using addresses it has looked up before, for up to ttl, instead of resolving them for every
+// connection.
+type Resolver struct {
+	Lookup LookupProvider
+	Dialer DialProvider
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	addresses []string
+	expires   time.Time
+}
+
+// New instantiates a Resolver which caches the addresses found by lookup for ttl, and dials them with dialer.
+func New(lookup LookupProvider, dialer DialProvider, ttl time.Duration) *Resolver {
+	return &Resolver{
+		Lookup:  lookup,
+		Dialer:  dialer,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// DialContext connects to the address, resolving its host from the cache. Each address of the host is tried in turn
+// until one connects.
+func (r *Resolver) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if net.ParseIP(host) != nil {
+		return r.Dialer.DialContext(ctx, network, address)
+	}
+
+	addresses, err := r.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+
+	for _, ip := range addresses {
+		conn, err = r.Dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
+// resolve returns the cached addresses of host, looking them up if they are missing or have expired.
+func (r *Resolver) resolve(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	cached, ok := r.entries[host]
+	r.mu.Unlock()
+
+	if ok && r.now().Before(cached.expires) {
+		return cached.addresses, nil
+	}
+
+	addresses, err := r.Lookup.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%v: %w", host, ErrNoAddresses)
+	}
+
+	r.mu.Lock()
+	r.entries[host] = entry{addresses: addresses, expires: r.now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return addresses, nil
+}
@@ -0,0 +1,145 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestResolver_DialContext_Success(t *testing.T) {
+	tests := []struct {
+		name              string
+		givenAddresses    []string
+		givenUnreachable  map[string]bool
+		givenDials        []string
+		givenElapsed      time.Duration
+		expectedLookups   int
+		expectedAddresses []string
+	}{
+		{
+			name:              "given a host dialled twice within the ttl, expect it looked up once",
+			givenAddresses:    []string{"10.0.0.1"},
+			givenDials:        []string{"example.com:443", "example.com:443"},
+			expectedLookups:   1,
+			expectedAddresses: []string{"10.0.0.1:443", "10.0.0.1:443"},
+		},
+		{
+			name:              "given a host dialled again after the ttl, expect it looked up again",
+			givenAddresses:    []string{"10.0.0.1"},
+			givenDials:        []string{"example.com:80", "example.com:80"},
+			givenElapsed:      time.Minute,
+			expectedLookups:   2,
+			expectedAddresses: []string{"10.0.0.1:80", "10.0.0.1:80"},
+		},
+		{
+			name:              "given an IP address, expect it dialled without a lookup",
+			givenDials:        []string{"10.0.0.2:80"},
+			expectedAddresses: []string{"10.0.0.2:80"},
+		},
+		{
+			name:              "given an unreachable address, expect the next one dialled",
+			givenAddresses:    []string{"10.0.0.1", "10.0.0.2"},
+			givenUnreachable:  map[string]bool{"10.0.0.1:80": true},
+			givenDials:        []string{"example.com:80"},
+			expectedLookups:   1,
+			expectedAddresses: []string{"10.0.0.1:80", "10.0.0.2:80"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lookup := &mockLookup{GivenAddresses: test.givenAddresses}
+			dialer := &mockDialer{GivenUnreachable: test.givenUnreachable}
+			now := time.Date(2021, 6, 10, 16, 0, 0, 0, time.UTC)
+
+			r := New(lookup, dialer, 30*time.Second)
+			r.now = func() time.Time { return now }
+
+			for _, address := range test.givenDials {
+				_, err := r.DialContext(context.Background(), "tcp", address)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				now = now.Add(test.givenElapsed)
+			}
+
+			if !cmp.Equal(lookup.lookups, test.expectedLookups) {
+				t.Fatal(cmp.Diff(lookup.lookups, test.expectedLookups))
+			}
+
+			if !cmp.Equal(dialer.addresses, test.expectedAddresses) {
+				t.Fatal(cmp.Diff(dialer.addresses, test.expectedAddresses))
+			}
+		})
+	}
+}
+
+func TestResolver_DialContext_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenLookup   *mockLookup
+		givenAddress  string
+		expectedError error
+	}{
+		{
+			name:          "given a host with no addresses, expect error",
+			givenLookup:   &mockLookup{},
+			givenAddress:  "example.com:80",
+			expectedError: ErrNoAddresses,
+		},
+		{
+			name:          "given the lookup fails, expect its error",
+			givenLookup:   &mockLookup{GivenError: errLookup},
+			givenAddress:  "example.com:80",
+			expectedError: errLookup,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.givenLookup, &mockDialer{}, time.Minute).DialContext(context.Background(), "tcp", test.givenAddress)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+var errLookup = errors.New("no such host")
+
+type mockLookup struct {
+	GivenAddresses []string
+	GivenError     error
+	lookups        int
+}
+
+func (m *mockLookup) LookupHost(_ context.Context, _ string) ([]string, error) {
+	m.lookups++
+
+	return m.GivenAddresses, m.GivenError
+}
+
+type mockDialer struct {
+	GivenUnreachable map[string]bool
+	addresses        []string
+}
+
+func (m *mockDialer) DialContext(_ context.Context, _, address string) (net.Conn, error) {
+	m.addresses = append(m.addresses, address)
+
+	if m.GivenUnreachable[address] {
+		return nil, errors.New("connection refused")
+	}
+
+	client, _ := net.Pipe()
+
+	return client, nil
+}
@@ -0,0 +1,113 @@
+package feedparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ErrFailedToParseXML is returned if the body is not well-formed XML.
+var (
+	ErrFailedToParseXML = errors.New("failed to parse xml")
+)
+
+// FeedParser finds all relevant URLs within XML documents such as RSS and Atom feeds.
+type FeedParser struct {
+	URLBuilder URLBuilder
+}
+
+// New instantiates a FeedParser.
+func New(builder URLBuilder) FeedParser {
+	return FeedParser{
+		URLBuilder: builder,
+	}
+}
+
+// URLBuilder removes the found url.URLs that should not be crawled and normalises the rest.
+type URLBuilder interface {
+	Clean([]*url.URL, *url.URL) []*url.URL
+}
+
+// textElements hold a URL as their text, such as an RSS <link> or a sitemap <loc>.
+var textElements = map[string]bool{
+	"link": true,
+	"loc":  true,
+}
+
+// attrElements hold a URL within an attribute, such as an Atom <link href> or an RSS <enclosure url>.
+var attrElements = map[string]string{
+	"link":      "href",
+	"enclosure": "url",
+	"content":   "src",
+}
+
+// FetchLinks finds all URLs within link, loc, enclosure and content elements of the XML document.
+func (f FeedParser) FetchLinks(ctx context.Context, body io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+	var found []*url.URL
+
+	decoder := xml.NewDecoder(body)
+	decoder.Strict = false
+
+	var inText bool
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseXML)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			inText = textElements[t.Name.Local]
+
+			if attr, ok := attrElements[t.Name.Local]; ok {
+				found = appendURL(found, attrValue(t, attr))
+			}
+		case xml.CharData:
+			if inText {
+				found = appendURL(found, string(t))
+			}
+		case xml.EndElement:
+			inText = false
+		}
+	}
+
+	span := urlbuilder.StartSpan(ctx, "URLBuilder.Clean")
+	defer span.End()
+
+	return f.URLBuilder.Clean(found, baseURL), nil
+}
+
+func attrValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// appendURL adds the raw URL if it is not blank and can be parsed.
+func appendURL(found []*url.URL, raw string) []*url.URL {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return found
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return found
+	}
+
+	return append(found, u)
+}
@@ -0,0 +1,88 @@
+package feedparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestFeedParser_FetchLinks_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenXML     io.Reader
+		givenURL     *url.URL
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given an RSS feed, expect channel, item and enclosure links returned",
+			givenXML: strings.NewReader(`<rss><channel><link>https://example.com/</link>
+				<item><link> https://example.com/post </link><enclosure url="/podcast.mp3"/></item>
+				<item><link>https://example.org/elsewhere</link></item></channel></rss>`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com"},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/"},
+				{Scheme: "https", Host: "example.com", Path: "/podcast.mp3"},
+				{Scheme: "https", Host: "example.com", Path: "/post/"},
+			},
+		},
+		{
+			name: "given an Atom feed, expect link hrefs returned",
+			givenXML: strings.NewReader(`<feed xmlns="http://www.w3.org/2005/Atom">
+				<link href="https://example.com/"/><entry><link rel="alternate" href="/entry"/></entry></feed>`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com"},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/"},
+				{Scheme: "https", Host: "example.com", Path: "/entry/"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(urlbuilder.New()).FetchLinks(context.Background(), test.givenXML, test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Path < actual[j].Path
+			})
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
+
+func TestFeedParser_FetchLinks_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenXML      io.Reader
+		expectedError error
+	}{
+		{
+			name:          "given malformed XML, expect error",
+			givenXML:      strings.NewReader(`<rss><channel><link>https://example.com/</link></rss`),
+			expectedError: ErrFailedToParseXML,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(urlbuilder.New()).FetchLinks(context.Background(), test.givenXML, &url.URL{Scheme: "https", Host: "example.com"})
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
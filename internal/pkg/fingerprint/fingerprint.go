@@ -0,0 +1,78 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is how many consecutive words make up each feature of a SimHash.
+const shingleSize = 3
+
+// Hash returns the SHA-256 of the text, ignoring differences in whitespace, so identical text gives the same hash.
+func Hash(text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// SimHash returns a 64-bit fingerprint of the text, ignoring case and punctuation, where similar text gives
+// fingerprints differing in few bits. An empty text gives zero.
+func SimHash(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	var weights [64]int
+
+	for _, feature := range shingles(words) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		for i := range weights {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+
+	for i, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(i)
+		}
+	}
+
+	return fingerprint
+}
+
+// Distance is the number of bits which differ between two SimHashes.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// shingles groups the words into overlapping runs of shingleSize, or a single run if there are fewer words.
+func shingles(words []string) []string {
+	if len(words) == 0 {
+		return nil
+	}
+
+	if len(words) <= shingleSize {
+		return []string{strings.Join(words, " ")}
+	}
+
+	found := make([]string, 0, len(words)-shingleSize+1)
+
+	for i := 0; i+shingleSize <= len(words); i++ {
+		found = append(found, strings.Join(words[i:i+shingleSize], " "))
+	}
+
+	return found
+}
@@ -0,0 +1,82 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var article = `The quick brown fox jumps over the lazy dog. It was a bright cold day in April, and the clocks were
+	striking thirteen. Call me Ishmael. Some years ago, never mind how long precisely, having little or no money in my
+	purse, and nothing particular to interest me on shore, I thought I would sail about a little and see the watery part
+	of the world.`
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenA     string
+		givenB     string
+		expectSame bool
+	}{
+		{
+			name:       "given the same text with different whitespace, expect the same hash",
+			givenA:     "Hello   there\n world",
+			givenB:     " Hello there world ",
+			expectSame: true,
+		},
+		{
+			name:       "given different text, expect different hashes",
+			givenA:     "Hello there world",
+			givenB:     "Hello there world!",
+			expectSame: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Hash(test.givenA) == Hash(test.givenB)
+
+			if !cmp.Equal(actual, test.expectSame) {
+				t.Fatal(cmp.Diff(actual, test.expectSame))
+			}
+		})
+	}
+}
+
+func TestSimHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenA      string
+		givenB      string
+		maxDistance int
+		minDistance int
+	}{
+		{
+			name:        "given text differing in case and punctuation, expect the same fingerprint",
+			givenA:      article,
+			givenB:      "THE QUICK BROWN FOX, jumps over the lazy dog!" + article[len("The quick brown fox jumps over the lazy dog."):],
+			maxDistance: 0,
+		},
+		{
+			name:        "given text with a small edit, expect a close fingerprint",
+			givenA:      article,
+			givenB:      article + " Updated on Monday.",
+			maxDistance: 6,
+		},
+		{
+			name:        "given unrelated text, expect a distant fingerprint",
+			givenA:      article,
+			givenB:      "Terms and conditions apply to every purchase made through this shop, including refunds and returns of goods.",
+			maxDistance: 64,
+			minDistance: 20,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Distance(SimHash(test.givenA), SimHash(test.givenB))
+
+			if actual > test.maxDistance || actual < test.minDistance {
+				t.Fatalf("expected a distance between %v and %v, got %v", test.minDistance, test.maxDistance, actual)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package htmlparser
 
 import (
+	"context"
+	"crawler/internal/domain"
+	"crawler/internal/pkg/urlbuilder"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
 
 	"github.com/antchfx/htmlquery"
 	"golang.org/x/net/html"
@@ -26,19 +30,188 @@ func New(builder URLBuilder) HTMLParser {
 	}
 }
 
-// URLBuilder takes all found hrefs and creates url.URLs with them.
+// URLBuilder takes all found anchors and creates a domain.Link for each that should be crawled.
 type URLBuilder interface {
-	Build([]*html.Node, *url.URL) ([]*url.URL, error)
+	BuildLinks([]*html.Node, *url.URL) ([]domain.Link, error)
 }
 
 // FetchLinks finds all relevant hrefs for a given http.Response's body and creates url.URL for each.
-func (h HTMLParser) FetchLinks(hr io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+func (h HTMLParser) FetchLinks(ctx context.Context, hr io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+	doc, err := h.Parse(ctx, hr, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Links, nil
+}
+
+// Parse finds all relevant hrefs for a given http.Response's body, along with each link's anchor and the page's
+// metadata, from a single parse of the body.
+func (h HTMLParser) Parse(ctx context.Context, hr io.Reader, baseURL *url.URL) (domain.Document, error) {
 	body, err := htmlquery.Parse(hr)
 	if err != nil {
-		return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseHTML)
+		return domain.Document{}, fmt.Errorf("%v: %w", err, ErrFailedToParseHTML)
+	}
+
+	anchors := htmlquery.Find(body, "//a[@href]")
+
+	span := urlbuilder.StartSpan(ctx, "URLBuilder.BuildLinks")
+	outlinks, err := h.URLBuilder.BuildLinks(anchors, baseURL)
+	span.End()
+
+	if err != nil {
+		return domain.Document{}, err
+	}
+
+	return domain.Document{
+		Links:    unique(outlinks),
+		Outlinks: outlinks,
+		Metadata: metadata(body, baseURL),
+		Text:     mainText(body),
+	}, nil
+}
+
+// unique returns the URL of every link, once each, in the order they were first found.
+func unique(links []domain.Link) []*url.URL {
+	var found []*url.URL
+	seen := make(map[url.URL]bool)
+
+	for i := range links {
+		if seen[links[i].URL] {
+			continue
+		}
+
+		seen[links[i].URL] = true
+		u := links[i].URL
+		found = append(found, &u)
 	}
 
-	hrefs := htmlquery.Find(body, "//a/@href")
+	return found
+}
+
+// metadata reads what the document says about itself. Canonical and hreflang URLs are resolved against baseURL but,
+// unlike links, are kept when they point at another host.
+func metadata(body *html.Node, baseURL *url.URL) domain.Metadata {
+	m := domain.Metadata{
+		H1:        texts(htmlquery.Find(body, "//h1")),
+		H2:        texts(htmlquery.Find(body, "//h2")),
+		WordCount: len(words(body, hidden)),
+	}
+
+	if title := htmlquery.FindOne(body, "//title"); title != nil {
+		m.Title = normalise(htmlquery.InnerText(title))
+	}
+
+	if root := htmlquery.FindOne(body, "//html"); root != nil {
+		m.Lang = strings.TrimSpace(htmlquery.SelectAttr(root, "lang"))
+	}
+
+	for _, meta := range htmlquery.Find(body, "//meta[@name]") {
+		if strings.EqualFold(htmlquery.SelectAttr(meta, "name"), "description") {
+			m.Description = normalise(htmlquery.SelectAttr(meta, "content"))
+			break
+		}
+	}
+
+	for _, link := range htmlquery.Find(body, "//link[@href]") {
+		rel := htmlquery.SelectAttr(link, "rel")
+
+		u, ok := resolve(htmlquery.SelectAttr(link, "href"), baseURL)
+		if !ok {
+			continue
+		}
+
+		if hasRel(rel, "canonical") && m.Canonical == (url.URL{}) {
+			m.Canonical = u
+		}
+
+		lang := strings.TrimSpace(htmlquery.SelectAttr(link, "hreflang"))
+		if hasRel(rel, "alternate") && lang != "" {
+			m.Alternates = append(m.Alternates, domain.Alternate{Lang: lang, URL: u})
+		}
+	}
+
+	return m
+}
+
+// texts returns the whitespace normalised text of each node, skipping any which are empty.
+func texts(nodes []*html.Node) []string {
+	var found []string
+
+	for _, n := range nodes {
+		if text := normalise(htmlquery.InnerText(n)); text != "" {
+			found = append(found, text)
+		}
+	}
+
+	return found
+}
+
+// hidden elements are never shown, so are not part of a page's text.
+var hidden = map[string]bool{"head": true, "script": true, "style": true, "noscript": true, "template": true}
+
+// boilerplate elements are hidden elements along with those repeated across a site, which are left out of the main
+// text.
+var boilerplate = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"nav": true, "header": true, "footer": true, "aside": true,
+}
+
+// mainText returns the words of the <main> element, or of the whole body if there is none, without boilerplate.
+func mainText(body *html.Node) string {
+	root := htmlquery.FindOne(body, "//main")
+	if root == nil {
+		root = body
+	}
+
+	return strings.Join(words(root, boilerplate), " ")
+}
+
+// words returns the words of the text within n, ignoring the text of any skipped elements.
+func words(n *html.Node, skip map[string]bool) []string {
+	if n.Type == html.ElementNode && skip[n.Data] {
+		return nil
+	}
+
+	if n.Type == html.TextNode {
+		return strings.Fields(n.Data)
+	}
+
+	var found []string
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		found = append(found, words(child, skip)...)
+	}
+
+	return found
+}
+
+// resolve parses a href relative to baseURL.
+func resolve(href string, baseURL *url.URL) (url.URL, bool) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || u.String() == "" {
+		return url.URL{}, false
+	}
+
+	if baseURL != nil {
+		u = baseURL.ResolveReference(u)
+	}
+
+	return *u, true
+}
+
+// hasRel reports whether a rel attribute, which may hold several space separated values, contains value.
+func hasRel(rel, value string) bool {
+	for _, v := range strings.Fields(rel) {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
 
-	return h.URLBuilder.Build(hrefs, baseURL)
+// normalise collapses all runs of whitespace into single spaces.
+func normalise(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
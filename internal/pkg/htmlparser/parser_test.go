@@ -1,7 +1,11 @@
 package htmlparser
 
 import (
+	"context"
+	"crawler/internal/domain"
+	"crawler/internal/pkg/tracing"
 	"crawler/internal/pkg/urlbuilder"
+	"fmt"
 	"io"
 	"net/url"
 	"strings"
@@ -12,6 +16,8 @@ import (
 	"golang.org/x/net/html"
 
 	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHTMLParser_FetchLinks_Success(t *testing.T) {
@@ -23,19 +29,22 @@ func TestHTMLParser_FetchLinks_Success(t *testing.T) {
 		expectedURLs    []*url.URL
 	}{
 		{
-			name:      "given valid HTML, expect URLs returned",
-			givenHTML: strings.NewReader("<html><a href='https://example.com/hi'>Hi</a></html>"),
+			name:      "given valid HTML, expect URLs returned once each",
+			givenHTML: strings.NewReader("<html><a href='https://example.com/hi'>Hi</a><a href='/hi'>Again</a></html>"),
 			givenURL: &url.URL{
 				Scheme: "https",
 				Host:   "example.com",
 				Path:   "/welcome",
 			},
 			givenURLBuilder: mockURLBuilder{
-				GivenURLs: []*url.URL{
+				GivenLinks: []domain.Link{
+					{
+						URL:  url.URL{Scheme: "https", Host: "example.com", Path: "/hi"},
+						Text: "Hi",
+					},
 					{
-						Scheme: "https",
-						Host:   "example.com",
-						Path:   "/hi",
+						URL:  url.URL{Scheme: "https", Host: "example.com", Path: "/hi"},
+						Text: "Again",
 					},
 				},
 			},
@@ -52,7 +61,7 @@ func TestHTMLParser_FetchLinks_Success(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			parser := New(test.givenURLBuilder)
 
-			urls, err := parser.FetchLinks(test.givenHTML, test.givenURL)
+			urls, err := parser.FetchLinks(context.Background(), test.givenHTML, test.givenURL)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -90,7 +99,7 @@ func TestHTMLParser_FetchLinks_Fail(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			parser := New(test.givenURLBuilder)
 
-			_, err := parser.FetchLinks(test.givenHTML, test.givenURL)
+			_, err := parser.FetchLinks(context.Background(), test.givenHTML, test.givenURL)
 			if err == nil {
 				t.Fatalf("expected %v, got nil", test.expectedError)
 			}
@@ -102,11 +111,190 @@ func TestHTMLParser_FetchLinks_Fail(t *testing.T) {
 	}
 }
 
+func TestHTMLParser_Parse_Outlinks(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenHTML        io.Reader
+		givenURL         *url.URL
+		expectedOutlinks []domain.Link
+	}{
+		{
+			name: "given anchors, expect an outlink for each with its anchor text and location",
+			givenHTML: strings.NewReader(`<html><body><nav><a href="/">Home</a></nav>
+				<p><a href="/" title="Home page">Click here</a><a href="/blank"></a></p></body></html>`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com", Path: "/welcome/"},
+			expectedOutlinks: []domain.Link{
+				{URL: url.URL{Scheme: "https", Host: "example.com", Path: "/"}, Text: "Home", Location: domain.LocationNav},
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+					Text:     "Click here",
+					Title:    "Home page",
+					Location: domain.LocationBody,
+				},
+				{URL: url.URL{Scheme: "https", Host: "example.com", Path: "/blank/"}, Location: domain.LocationBody},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := New(urlbuilder.New()).Parse(context.Background(), test.givenHTML, test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(doc.Outlinks, test.expectedOutlinks) {
+				t.Fatal(cmp.Diff(doc.Outlinks, test.expectedOutlinks))
+			}
+		})
+	}
+}
+
+func TestHTMLParser_Parse_Text(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenHTML    io.Reader
+		expectedText string
+	}{
+		{
+			name: "given a main element, expect only its text without boilerplate",
+			givenHTML: strings.NewReader(`<html><body><nav>Home About</nav><main><header>Posted today</header>
+				<h1>Title</h1><p>The   main
+				text.</p><script>ignored()</script></main><footer>Copyright</footer></body></html>`),
+			expectedText: "Title The main text.",
+		},
+		{
+			name: "given no main element, expect the body text without boilerplate",
+			givenHTML: strings.NewReader(`<html><head><title>Ignored</title></head><body><nav>Home</nav>
+				<p>Body text.</p><aside>Related</aside></body></html>`),
+			expectedText: "Body text.",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := New(mockURLBuilder{}).Parse(context.Background(), test.givenHTML, &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(doc.Text, test.expectedText) {
+				t.Fatal(cmp.Diff(doc.Text, test.expectedText))
+			}
+		})
+	}
+}
+
+func TestHTMLParser_Parse_Span(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenTraced   bool
+		expectedSpans []string
+	}{
+		{
+			name:          "given a traced page, expect building its links to be a child span",
+			givenTraced:   true,
+			expectedSpans: []string{"URLBuilder.BuildLinks <- Parser.Parse", "Parser.Parse <- "},
+		},
+		{
+			name: "given a page which is not traced, expect no spans",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			ctx := context.Background()
+
+			var parent trace.Span
+			if test.givenTraced {
+				ctx, parent = tracing.NewWithExporter(exporter).Tracer("test").Start(ctx, "Parser.Parse")
+			}
+
+			_, err := New(urlbuilder.New()).Parse(ctx, strings.NewReader(`<a href="/a/">A</a>`), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if parent != nil {
+				parent.End()
+			}
+
+			names := make(map[string]string)
+			for _, span := range exporter.GetSpans() {
+				names[span.SpanContext.SpanID().String()] = span.Name
+			}
+
+			var actual []string
+			for _, span := range exporter.GetSpans() {
+				actual = append(actual, fmt.Sprintf("%v <- %v", span.Name, names[span.Parent.SpanID().String()]))
+			}
+
+			if !cmp.Equal(actual, test.expectedSpans) {
+				t.Fatal(cmp.Diff(actual, test.expectedSpans))
+			}
+		})
+	}
+}
+
+func TestHTMLParser_Parse_Metadata(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenHTML        io.Reader
+		givenURL         *url.URL
+		expectedMetadata domain.Metadata
+	}{
+		{
+			name: "given HTML with metadata, expect it read from the document",
+			givenHTML: strings.NewReader(`<!DOCTYPE html><html lang="en-GB"><head>
+				<title> Welcome
+				home </title>
+				<meta name="Description" content="All about us">
+				<link rel="canonical" href="/welcome/">
+				<link rel="alternate" hreflang="fr" href="https://example.fr/bienvenue/">
+				<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+				<style>body { color: red }</style></head>
+				<body><h1>Hello <em>there</em></h1><h2>One</h2><h2> </h2><h2>Two</h2>
+				<script>var ignored = "many words here";</script><p>Some more words.</p></body></html>`),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com", Path: "/welcome"},
+			expectedMetadata: domain.Metadata{
+				Title:       "Welcome home",
+				Description: "All about us",
+				Canonical:   url.URL{Scheme: "https", Host: "example.com", Path: "/welcome/"},
+				Alternates: []domain.Alternate{
+					{Lang: "fr", URL: url.URL{Scheme: "https", Host: "example.fr", Path: "/bienvenue/"}},
+				},
+				H1:        []string{"Hello there"},
+				H2:        []string{"One", "Two"},
+				Lang:      "en-GB",
+				WordCount: 7,
+			},
+		},
+		{
+			name:             "given HTML without metadata, expect only a word count",
+			givenHTML:        strings.NewReader("<html><a href='https://example.com/hi'>Hi</a></html>"),
+			givenURL:         &url.URL{Scheme: "https", Host: "example.com", Path: "/welcome"},
+			expectedMetadata: domain.Metadata{WordCount: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := New(mockURLBuilder{})
+
+			doc, err := parser.Parse(context.Background(), test.givenHTML, test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(doc.Metadata, test.expectedMetadata) {
+				t.Fatal(cmp.Diff(doc.Metadata, test.expectedMetadata))
+			}
+		})
+	}
+}
+
 type mockURLBuilder struct {
-	GivenURLs  []*url.URL
+	GivenLinks []domain.Link
 	GivenError error
 }
 
-func (m mockURLBuilder) Build(_ []*html.Node, _ *url.URL) ([]*url.URL, error) {
-	return m.GivenURLs, m.GivenError
+func (m mockURLBuilder) BuildLinks(_ []*html.Node, _ *url.URL) ([]domain.Link, error) {
+	return m.GivenLinks, m.GivenError
 }
@@ -5,8 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // Errors returned by the Client.
@@ -14,8 +20,13 @@ var (
 	ErrFailedRequest        = errors.New("failed to get response")
 	ErrFailedToBuildRequest = errors.New("failed to build request")
 	ErrUnacceptableResponse = errors.New("invalid status code received")
+	ErrFailedToAuthenticate = errors.New("failed to authenticate request")
+	ErrTimeout              = fmt.Errorf("request timed out: %w", ErrFailedRequest)
 )
 
+// maxDrainSize is the most of an unread body that will be discarded to allow its connection to be reused.
+const maxDrainSize = 64 << 10
+
 // Doer sends a http.Request and returns a http.Response.
 type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -26,45 +37,225 @@ type Requester interface {
 	WithURL(url url.URL) Requester
 	WithBody(body io.Reader) Requester
 	WithMethod(method string) Requester
+	WithHeader(key, value string) Requester
+	WithHeaders(header http.Header) Requester
 	Build(ctx context.Context) (*http.Request, error)
 }
 
-// Client builds a request for a given url.URL and returns the response.
+// Authenticator adds credentials to a http.Request.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// MetricsProvider observes the responses received.
+type MetricsProvider interface {
+	ObserveResponse(host string, statusCode int, duration time.Duration)
+}
+
+// Client builds a request for a given url.URL and returns the response. Requests are built one at a time, as the
+// Requester is shared by every fetch.
 type Client struct {
 	Doer      Doer
 	Requester Requester
+
+	Metrics MetricsProvider
+	Logger  log.FieldLogger
+
+	authenticators map[string]Authenticator
+	mu             sync.Mutex
+}
+
+// Option configures optional behaviour of a Client.
+type Option func(c *Client)
+
+// WithAuthenticator authenticates the https requests to the given hosts, and only those, with a. A host without a port
+// matches the host on any port. Requests over plain http are only authenticated for a host given as http://host.
+func WithAuthenticator(a Authenticator, hosts ...string) Option {
+	return func(c *Client) {
+		for _, host := range hosts {
+			c.authenticators[authKey(host)] = a
+		}
+	}
+}
+
+// WithMetrics observes the status code and latency of every response with metrics.
+func WithMetrics(metrics MetricsProvider) Option {
+	return func(c *Client) {
+		c.Metrics = metrics
+	}
+}
+
+// WithLogger writes a line for every request to logger, with its url, status and duration.
+func WithLogger(logger log.FieldLogger) Option {
+	return func(c *Client) {
+		c.Logger = logger
+	}
 }
 
 // New instantiates a Client.
-func New(doer Doer, requester Requester) *Client {
-	return &Client{
-		Doer:      doer,
-		Requester: requester,
+func New(doer Doer, requester Requester, opts ...Option) *Client {
+	c := &Client{
+		Doer:           doer,
+		Requester:      requester,
+		Metrics:        noMetrics{},
+		Logger:         log.StandardLogger(),
+		authenticators: make(map[string]Authenticator),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
 // Fetch performs a http.MethodGet for a given url.URL.
 func (c *Client) Fetch(ctx context.Context, u url.URL) (*http.Response, error) {
+	return c.FetchIfModified(ctx, u, "", "")
+}
+
+// FetchIfModified performs a conditional http.MethodGet for a given url.URL, using the ETag and Last-Modified of a
+// previous response. A http.StatusNotModified response is returned, without a body, if the URL is unchanged.
+func (c *Client) FetchIfModified(ctx context.Context, u url.URL, etag, lastModified string) (*http.Response, error) {
+	c.mu.Lock()
 	req, err := c.Requester.
 		WithMethod(http.MethodGet).
 		WithURL(u).
 		Build(ctx)
+	c.mu.Unlock()
+
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", err, ErrFailedToBuildRequest)
 	}
 
+	if a, ok := c.authenticator(u); ok {
+		err = a.Authenticate(req)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrFailedToAuthenticate)
+		}
+	}
+
+	conditional := etag != "" || lastModified != ""
+
+	if conditional {
+		if req.Header == nil {
+			req.Header = make(http.Header)
+		}
+
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	start := time.Now()
+
 	res, err := c.Doer.Do(req)
+	duration := time.Since(start)
+
 	if err != nil {
+		c.Logger.WithFields(log.Fields{
+			"url":      u.String(),
+			"duration": duration.Seconds(),
+		}).WithError(err).Debug("request failed")
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("%v: %w", err, ErrTimeout)
+		}
+
 		return nil, fmt.Errorf("%v: %w", err, ErrFailedRequest)
 	}
 
+	c.Metrics.ObserveResponse(u.Host, res.StatusCode, duration)
+	c.Logger.WithFields(log.Fields{
+		"url":      u.String(),
+		"status":   res.StatusCode,
+		"duration": duration.Seconds(),
+	}).Debug("received response")
+
+	if conditional && res.StatusCode == http.StatusNotModified {
+		return res, nil
+	}
+
 	if !isAcceptableStatus(res.StatusCode) {
-		return nil, ErrUnacceptableResponse
+		closeBody(res.Body)
+
+		return nil, &StatusError{
+			StatusCode: res.StatusCode,
+			Redirects:  Redirects(res),
+		}
 	}
 
 	return res, nil
 }
 
+// authenticator finds the Authenticator for the scheme and host of u, matching its port first.
+func (c *Client) authenticator(u url.URL) (Authenticator, bool) {
+	if a, ok := c.authenticators[authKey(u.Scheme+"://"+u.Host)]; ok {
+		return a, true
+	}
+
+	a, ok := c.authenticators[authKey(u.Scheme+"://"+u.Hostname())]
+
+	return a, ok
+}
+
+// authKey normalises a host to scheme://host, with https as the scheme of a host given without one.
+func authKey(host string) string {
+	host = strings.ToLower(host)
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	return host
+}
+
+// noMetrics observes nothing, for a Client without metrics.
+type noMetrics struct{}
+
+func (noMetrics) ObserveResponse(_ string, _ int, _ time.Duration) {}
+
+// StatusError is returned if the response does not have an acceptable status code. It wraps ErrUnacceptableResponse.
+type StatusError struct {
+	StatusCode int
+	Redirects  []url.URL
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v: %v", e.StatusCode, ErrUnacceptableResponse)
+}
+
+func (e *StatusError) Unwrap() error {
+	return ErrUnacceptableResponse
+}
+
+// Redirects returns every URL the request was redirected to before the response, in the order they were followed.
+// The last is the URL of the response.
+func Redirects(res *http.Response) []url.URL {
+	var chain []url.URL
+
+	for req := res.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		chain = append([]url.URL{*req.URL}, chain...)
+	}
+
+	return chain
+}
+
+// closeBody discards what is left of a body that will not be read, up to maxDrainSize, so the connection can be
+// reused, then closes it.
+func closeBody(body io.ReadCloser) {
+	if body == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxDrainSize))
+	_ = body.Close()
+}
+
 func isAcceptableStatus(status int) bool {
 	return status == http.StatusOK
 }
@@ -2,13 +2,20 @@ package httpclient
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 )
 
 func TestClient_Fetch_Success(t *testing.T) {
@@ -96,6 +103,7 @@ func TestClient_Fetch_Fail(t *testing.T) {
 			givenDoer: mockDoer{
 				GivenDoResponse: &http.Response{
 					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader("not found")),
 				},
 			},
 			expectedError: ErrUnacceptableResponse,
@@ -117,6 +125,355 @@ func TestClient_Fetch_Fail(t *testing.T) {
 	}
 }
 
+func TestClient_Fetch_Timeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenDoError  error
+		expectedError []error
+	}{
+		{
+			name:          "given the request times out, expect a timeout which is also a failed request",
+			givenDoError:  &url.Error{Op: "Get", URL: "https://example.com", Err: mockTimeoutError{}},
+			expectedError: []error{ErrTimeout, ErrFailedRequest},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := New(mockDoer{GivenDoError: test.givenDoError}, mockRequester{GivenBuildRequest: &http.Request{}})
+
+			_, err := client.Fetch(context.Background(), url.URL{Host: "example.com"})
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			for _, expected := range test.expectedError {
+				if !cmp.Equal(err, expected, cmpopts.EquateErrors()) {
+					t.Fatal(cmp.Diff(err, expected, cmpopts.EquateErrors()))
+				}
+			}
+		})
+	}
+}
+
+func TestClient_WithMetrics(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenStatusCode  int
+		expectedObserved []string
+	}{
+		{
+			name:             "given a response, expect its host and status code observed",
+			givenStatusCode:  http.StatusNotFound,
+			expectedObserved: []string{"example.com 404"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := &mockMetrics{}
+			client := New(
+				mockDoer{GivenDoResponse: &http.Response{StatusCode: test.givenStatusCode}},
+				mockRequester{GivenBuildRequest: &http.Request{}},
+				WithMetrics(metrics),
+			)
+
+			_, _ = client.Fetch(context.Background(), url.URL{Host: "example.com"})
+
+			if !cmp.Equal(metrics.observed, test.expectedObserved) {
+				t.Fatal(cmp.Diff(metrics.observed, test.expectedObserved))
+			}
+		})
+	}
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenResponse  *http.Response
+		givenDoError   error
+		expectedLevel  log.Level
+		expectedMsg    string
+		expectedFields []string
+	}{
+		{
+			name:           "given a response, expect its url and status logged",
+			givenResponse:  &http.Response{StatusCode: http.StatusOK},
+			expectedLevel:  log.DebugLevel,
+			expectedMsg:    "received response",
+			expectedFields: []string{"duration", "status", "url"},
+		},
+		{
+			name:           "given the request fails, expect its url and error logged",
+			givenDoError:   errors.New("connection refused"),
+			expectedLevel:  log.DebugLevel,
+			expectedMsg:    "request failed",
+			expectedFields: []string{"duration", "error", "url"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger, hook := logtest.NewNullLogger()
+			logger.SetLevel(log.DebugLevel)
+
+			client := New(
+				mockDoer{GivenDoResponse: test.givenResponse, GivenDoError: test.givenDoError},
+				mockRequester{GivenBuildRequest: &http.Request{}},
+				WithLogger(logger),
+			)
+
+			_, _ = client.Fetch(context.Background(), url.URL{Scheme: "https", Host: "example.com"})
+
+			entry := hook.LastEntry()
+			if entry == nil {
+				t.Fatal("expected a log entry, got none")
+			}
+
+			var fields []string
+			for field := range entry.Data {
+				fields = append(fields, field)
+			}
+
+			sort.Strings(fields)
+
+			if !cmp.Equal(entry.Level, test.expectedLevel) {
+				t.Fatal(cmp.Diff(entry.Level, test.expectedLevel))
+			}
+
+			if !cmp.Equal(entry.Message, test.expectedMsg) {
+				t.Fatal(cmp.Diff(entry.Message, test.expectedMsg))
+			}
+
+			if !cmp.Equal(fields, test.expectedFields) {
+				t.Fatal(cmp.Diff(fields, test.expectedFields))
+			}
+
+			if !cmp.Equal(entry.Data["url"], "https://example.com") {
+				t.Fatal(cmp.Diff(entry.Data["url"], "https://example.com"))
+			}
+		})
+	}
+}
+
+func TestClient_Fetch_StatusError(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenResponse *http.Response
+		expectedError *StatusError
+	}{
+		{
+			name: "given a redirect to a missing page, expect its status code and the redirects followed",
+			givenResponse: &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("not found")),
+				Request: &http.Request{
+					URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/c/"},
+					Response: &http.Response{
+						Request: &http.Request{
+							URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/b/"},
+							Response: &http.Response{
+								Request: &http.Request{
+									URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/a/"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedError: &StatusError{
+				StatusCode: http.StatusNotFound,
+				Redirects: []url.URL{
+					{Scheme: "https", Host: "example.com", Path: "/b/"},
+					{Scheme: "https", Host: "example.com", Path: "/c/"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := New(mockDoer{GivenDoResponse: test.givenResponse}, mockRequester{})
+
+			_, err := client.Fetch(context.Background(), url.URL{Scheme: "https", Host: "example.com", Path: "/a/"})
+
+			var actual *StatusError
+			if !errors.As(err, &actual) {
+				t.Fatalf("expected %v, got %v", test.expectedError, err)
+			}
+
+			if !cmp.Equal(actual, test.expectedError) {
+				t.Fatal(cmp.Diff(actual, test.expectedError))
+			}
+
+			if !errors.Is(err, ErrUnacceptableResponse) {
+				t.Fatalf("expected %v to wrap %v", err, ErrUnacceptableResponse)
+			}
+		})
+	}
+}
+
+func TestClient_FetchIfModified(t *testing.T) {
+	tests := []struct {
+		name               string
+		givenETag          string
+		givenLastModified  string
+		givenStatusCode    int
+		expectedHeader     http.Header
+		expectedStatusCode int
+		expectedError      error
+	}{
+		{
+			name:               "given an ETag and Last-Modified, expect them sent and a 304 returned",
+			givenETag:          `"abc"`,
+			givenLastModified:  "Thu, 10 Jun 2021 16:00:00 GMT",
+			givenStatusCode:    http.StatusNotModified,
+			expectedHeader:     http.Header{"If-None-Match": {`"abc"`}, "If-Modified-Since": {"Thu, 10 Jun 2021 16:00:00 GMT"}},
+			expectedStatusCode: http.StatusNotModified,
+		},
+		{
+			name:               "given an ETag that no longer matches, expect the 200 returned",
+			givenETag:          `"abc"`,
+			givenStatusCode:    http.StatusOK,
+			expectedHeader:     http.Header{"If-None-Match": {`"abc"`}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:            "given no ETag or Last-Modified, expect a 304 to be unacceptable",
+			givenStatusCode: http.StatusNotModified,
+			expectedHeader:  http.Header{},
+			expectedError:   ErrUnacceptableResponse,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := &http.Request{Header: http.Header{}}
+			client := New(
+				mockDoer{GivenDoResponse: &http.Response{StatusCode: test.givenStatusCode, Body: io.NopCloser(strings.NewReader(""))}},
+				mockRequester{GivenBuildRequest: req},
+			)
+
+			res, err := client.FetchIfModified(context.Background(), url.URL{Host: "example.com"}, test.givenETag, test.givenLastModified)
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(req.Header, test.expectedHeader) {
+				t.Fatal(cmp.Diff(req.Header, test.expectedHeader))
+			}
+
+			if test.expectedError == nil && !cmp.Equal(res.StatusCode, test.expectedStatusCode) {
+				t.Fatal(cmp.Diff(res.StatusCode, test.expectedStatusCode))
+			}
+		})
+	}
+}
+
+func TestClient_WithAuthenticator(t *testing.T) {
+	tests := []struct {
+		name               string
+		givenAuthenticator mockAuthenticator
+		givenHosts         []string
+		givenURL           url.URL
+		expectedHeader     http.Header
+		expectedError      error
+	}{
+		{
+			name:               "given a URL on an authenticated host, expect the request authenticated",
+			givenAuthenticator: mockAuthenticator{},
+			givenHosts:         []string{"example.com"},
+			givenURL:           url.URL{Scheme: "https", Host: "example.com"},
+			expectedHeader:     http.Header{"Authorization": {"mock"}},
+		},
+		{
+			name:               "given a URL with a port on an authenticated host, expect the request authenticated",
+			givenAuthenticator: mockAuthenticator{},
+			givenHosts:         []string{"Example.com"},
+			givenURL:           url.URL{Scheme: "https", Host: "example.com:8080"},
+			expectedHeader:     http.Header{"Authorization": {"mock"}},
+		},
+		{
+			name:               "given a URL on another host, expect the request not authenticated",
+			givenAuthenticator: mockAuthenticator{},
+			givenHosts:         []string{"example.com"},
+			givenURL:           url.URL{Scheme: "https", Host: "other.com"},
+			expectedHeader:     http.Header{},
+		},
+		{
+			name:               "given a plain http URL on an authenticated host, expect the request not authenticated",
+			givenAuthenticator: mockAuthenticator{},
+			givenHosts:         []string{"example.com"},
+			givenURL:           url.URL{Scheme: "http", Host: "example.com"},
+			expectedHeader:     http.Header{},
+		},
+		{
+			name:               "given a plain http URL on a host authenticated over http, expect the request authenticated",
+			givenAuthenticator: mockAuthenticator{},
+			givenHosts:         []string{"http://example.com"},
+			givenURL:           url.URL{Scheme: "http", Host: "example.com:80"},
+			expectedHeader:     http.Header{"Authorization": {"mock"}},
+		},
+		{
+			name:               "given a https URL on a host authenticated over http, expect the request not authenticated",
+			givenAuthenticator: mockAuthenticator{},
+			givenHosts:         []string{"http://example.com"},
+			givenURL:           url.URL{Scheme: "https", Host: "example.com"},
+			expectedHeader:     http.Header{},
+		},
+		{
+			name:               "given the authenticator fails, expect error",
+			givenAuthenticator: mockAuthenticator{GivenError: errors.New("login rejected")},
+			givenHosts:         []string{"example.com"},
+			givenURL:           url.URL{Scheme: "https", Host: "example.com"},
+			expectedHeader:     http.Header{},
+			expectedError:      ErrFailedToAuthenticate,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := &http.Request{Header: http.Header{}}
+			client := New(
+				mockDoer{GivenDoResponse: &http.Response{StatusCode: http.StatusOK}},
+				mockRequester{GivenBuildRequest: req},
+				WithAuthenticator(test.givenAuthenticator, test.givenHosts...),
+			)
+
+			_, err := client.Fetch(context.Background(), test.givenURL)
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(req.Header, test.expectedHeader) {
+				t.Fatal(cmp.Diff(req.Header, test.expectedHeader))
+			}
+		})
+	}
+}
+
+type mockTimeoutError struct{}
+
+func (mockTimeoutError) Error() string   { return "timeout" }
+func (mockTimeoutError) Timeout() bool   { return true }
+func (mockTimeoutError) Temporary() bool { return true }
+
+type mockMetrics struct {
+	observed []string
+}
+
+func (m *mockMetrics) ObserveResponse(host string, statusCode int, _ time.Duration) {
+	m.observed = append(m.observed, fmt.Sprintf("%v %v", host, statusCode))
+}
+
+type mockAuthenticator struct {
+	GivenError error
+}
+
+func (m mockAuthenticator) Authenticate(req *http.Request) error {
+	if m.GivenError != nil {
+		return m.GivenError
+	}
+
+	req.Header.Set("Authorization", "mock")
+
+	return nil
+}
+
 type mockRequester struct {
 	GivenBuildRequest *http.Request
 	GivenBuildError   error
@@ -134,6 +491,14 @@ func (m mockRequester) WithMethod(_ string) Requester {
 	return m
 }
 
+func (m mockRequester) WithHeader(_, _ string) Requester {
+	return m
+}
+
+func (m mockRequester) WithHeaders(_ http.Header) Requester {
+	return m
+}
+
 func (m mockRequester) Build(_ context.Context) (*http.Request, error) {
 	return m.GivenBuildRequest, m.GivenBuildError
 }
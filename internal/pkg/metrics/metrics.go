@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the name of every metric.
+const namespace = "crawler"
+
+// Metrics counts the progress of a crawl and the behaviour of its HTTP requests.
+type Metrics struct {
+	registry      *prometheus.Registry
+	queued        prometheus.Counter
+	fetched       prometheus.Counter
+	inFlight      prometheus.Gauge
+	errors        *prometheus.CounterVec
+	responses     *prometheus.CounterVec
+	bytes         prometheus.Counter
+	fetchDuration *prometheus.HistogramVec
+}
+
+// New instantiates Metrics registered with a registry of their own.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		queued: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pages_queued_total",
+			Help:      "Pages queued to be fetched.",
+		}),
+		fetched: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pages_fetched_total",
+			Help:      "Pages fetched, whether or not they succeeded.",
+		}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "fetches_in_flight",
+			Help:      "Pages being fetched.",
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Pages which failed, by the class of error.",
+		}, []string{"class"}),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_responses_total",
+			Help:      "HTTP responses, by status code.",
+		}, []string{"code"}),
+		bytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "response_bytes_total",
+			Help:      "Bytes of response bodies read.",
+		}),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fetch_duration_seconds",
+			Help:      "Time taken to receive the headers of a response, by host.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"host"}),
+	}
+
+	m.registry.MustRegister(m.queued, m.fetched, m.inFlight, m.errors, m.responses, m.bytes, m.fetchDuration)
+
+	return m
+}
+
+// PageQueued counts a page queued to be fetched.
+func (m *Metrics) PageQueued() {
+	m.queued.Inc()
+}
+
+// FetchStarted counts a page being fetched.
+func (m *Metrics) FetchStarted() {
+	m.inFlight.Inc()
+}
+
+// FetchFinished counts a fetched page, and the class of its error if it failed.
+func (m *Metrics) FetchFinished(errorClass string) {
+	m.inFlight.Dec()
+	m.fetched.Inc()
+
+	if errorClass != "" {
+		m.errors.WithLabelValues(errorClass).Inc()
+	}
+}
+
+// BodyRead counts the bytes read of a response body.
+func (m *Metrics) BodyRead(bytes int) {
+	m.bytes.Add(float64(bytes))
+}
+
+// ObserveResponse counts the status code of a response from host, and how long it took.
+func (m *Metrics) ObserveResponse(host string, statusCode int, duration time.Duration) {
+	m.responses.WithLabelValues(strconv.Itoa(statusCode)).Inc()
+	m.fetchDuration.WithLabelValues(host).Observe(duration.Seconds())
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
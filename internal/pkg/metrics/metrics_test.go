@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Handler(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenObserved func(m *Metrics)
+		expectedLines []string
+	}{
+		{
+			name: "given a crawl's progress and responses, expect them in the Prometheus text format",
+			givenObserved: func(m *Metrics) {
+				m.PageQueued()
+				m.PageQueued()
+				m.FetchStarted()
+				m.FetchStarted()
+				m.BodyRead(512)
+				m.FetchFinished("")
+				m.FetchFinished("timeout")
+				m.FetchStarted()
+				m.ObserveResponse("example.com", http.StatusOK, 200*time.Millisecond)
+				m.ObserveResponse("example.com", http.StatusNotFound, 20*time.Millisecond)
+			},
+			expectedLines: []string{
+				"crawler_pages_queued_total 2",
+				"crawler_pages_fetched_total 2",
+				"crawler_fetches_in_flight 1",
+				`crawler_errors_total{class="timeout"} 1`,
+				`crawler_http_responses_total{code="200"} 1`,
+				`crawler_http_responses_total{code="404"} 1`,
+				"crawler_response_bytes_total 512",
+				`crawler_fetch_duration_seconds_count{host="example.com"} 2`,
+				`crawler_fetch_duration_seconds_bucket{host="example.com",le="0.025"} 1`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := New()
+			test.givenObserved(m)
+
+			rec := httptest.NewRecorder()
+			m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+			body, err := io.ReadAll(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, line := range test.expectedLines {
+				if !strings.Contains(string(body), line+"\n") {
+					t.Fatalf("expected %q in:\n%v", line, string(body))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package parserregistry
+
+import (
+	"bytes"
+	"context"
+	"crawler/internal/domain"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsupportedContentType is returned if no Parser has been registered for a content type.
+var (
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// sniffLen is the most bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// Parser finds all the desired URLs within a body of a single content type.
+type Parser interface {
+	FetchLinks(ctx context.Context, body io.Reader, baseURL *url.URL) ([]*url.URL, error)
+}
+
+// DocumentParser finds all the desired URLs within a body along with what the body says about its page.
+type DocumentParser interface {
+	Parse(ctx context.Context, body io.Reader, baseURL *url.URL) (domain.Document, error)
+}
+
+// Registry picks the Parser for a body by its content type.
+type Registry struct {
+	parsers map[string]Parser
+}
+
+// New instantiates an empty Registry.
+func New() *Registry {
+	return &Registry{
+		parsers: make(map[string]Parser),
+	}
+}
+
+// Register uses the Parser for every body of the given MIME types, replacing any Parser registered before.
+func (r *Registry) Register(parser Parser, mimeTypes ...string) *Registry {
+	for _, mimeType := range mimeTypes {
+		r.parsers[strings.ToLower(mimeType)] = parser
+	}
+
+	return r
+}
+
+// Supports reports whether a body of the content type can be parsed. A missing or generic content type is
+// supported, as the type is sniffed from the body.
+func (r *Registry) Supports(contentType string) bool {
+	if needsSniffing(contentType) {
+		return true
+	}
+
+	_, ok := r.lookup(contentType)
+
+	return ok
+}
+
+// Parse finds all the desired URLs within the body, and any metadata, using the Parser registered for its content
+// type.
+func (r *Registry) Parse(ctx context.Context, body io.Reader, contentType string, baseURL *url.URL) (domain.Document, error) {
+	if needsSniffing(contentType) {
+		contentType, body = sniff(body)
+	}
+
+	parser, ok := r.lookup(contentType)
+	if !ok {
+		return domain.Document{}, fmt.Errorf("%v: %w", contentType, ErrUnsupportedContentType)
+	}
+
+	return parse(ctx, parser, body, baseURL)
+}
+
+// parse uses the Parser as a DocumentParser if it is one, otherwise the Document only has links.
+func parse(ctx context.Context, parser Parser, body io.Reader, baseURL *url.URL) (domain.Document, error) {
+	if p, ok := parser.(DocumentParser); ok {
+		return p.Parse(ctx, body, baseURL)
+	}
+
+	links, err := parser.FetchLinks(ctx, body, baseURL)
+	if err != nil {
+		return domain.Document{}, err
+	}
+
+	return domain.Document{Links: links}, nil
+}
+
+func (r *Registry) lookup(contentType string) (Parser, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	parser, ok := r.parsers[mediaType]
+
+	return parser, ok
+}
+
+// needsSniffing reports whether the content type says too little about the body to pick a Parser.
+func needsSniffing(contentType string) bool {
+	return contentType == "" || strings.HasPrefix(strings.ToLower(contentType), "application/octet-stream")
+}
+
+// sniff detects the content type from the start of the body and returns a reader of the whole body.
+func sniff(body io.Reader) (string, io.Reader) {
+	start := make([]byte, sniffLen)
+
+	n, _ := io.ReadFull(body, start)
+	start = start[:n]
+
+	return http.DetectContentType(start), io.MultiReader(bytes.NewReader(start), body)
+}
+
+// Chain runs every Parser over the same body and combines the URLs they find, so a content type can be parsed for
+// more than one kind of link. The outlinks, metadata and text are taken from the first DocumentParser.
+type Chain []Parser
+
+// FetchLinks finds all the URLs found by each Parser of the Chain, without duplicates.
+func (c Chain) FetchLinks(ctx context.Context, body io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+	doc, err := c.Parse(ctx, body, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Links, nil
+}
+
+// Parse finds all the URLs found by each Parser of the Chain, without duplicates, along with the outlinks, metadata
+// and text.
+func (c Chain) Parse(ctx context.Context, body io.Reader, baseURL *url.URL) (domain.Document, error) {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return domain.Document{}, err
+	}
+
+	var found domain.Document
+	hasMetadata := false
+	seen := make(map[url.URL]bool)
+
+	for _, parser := range c {
+		doc, err := parse(ctx, parser, bytes.NewReader(b), baseURL)
+		if err != nil {
+			return domain.Document{}, err
+		}
+
+		if _, ok := parser.(DocumentParser); ok && !hasMetadata {
+			found.Outlinks = doc.Outlinks
+			found.Metadata = doc.Metadata
+			found.Text = doc.Text
+			hasMetadata = true
+		}
+
+		for _, link := range doc.Links {
+			if seen[*link] {
+				continue
+			}
+
+			seen[*link] = true
+			found.Links = append(found.Links, link)
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,237 @@
+package parserregistry
+
+import (
+	"context"
+	"crawler/internal/domain"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRegistry_FetchLinks_Success(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenBody        io.Reader
+		givenContentType string
+		expectedURLs     []*url.URL
+		expectedBody     string
+	}{
+		{
+			name:             "given a registered content type with parameters, expect its parser used",
+			givenBody:        strings.NewReader("body { background: url(/bg.png) }"),
+			givenContentType: "Text/CSS; charset=utf-8",
+			expectedURLs:     []*url.URL{{Path: "text/css"}},
+			expectedBody:     "body { background: url(/bg.png) }",
+		},
+		{
+			name:         "given no content type, expect the parser for the sniffed type given the whole body",
+			givenBody:    strings.NewReader("<!DOCTYPE html><html><a href='/hi'>Hi</a></html>"),
+			expectedURLs: []*url.URL{{Path: "text/html"}},
+			expectedBody: "<!DOCTYPE html><html><a href='/hi'>Hi</a></html>",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			html := &mockParser{GivenURLs: []*url.URL{{Path: "text/html"}}}
+			css := &mockParser{GivenURLs: []*url.URL{{Path: "text/css"}}}
+
+			registry := New().
+				Register(html, "text/html").
+				Register(css, "text/css")
+
+			doc, err := registry.Parse(context.Background(), test.givenBody, test.givenContentType, &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := doc.Links
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+
+			read := html.read + css.read
+			if !cmp.Equal(read, test.expectedBody) {
+				t.Fatal(cmp.Diff(read, test.expectedBody))
+			}
+		})
+	}
+}
+
+func TestRegistry_FetchLinks_Fail(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenBody        io.Reader
+		givenContentType string
+		expectedError    error
+	}{
+		{
+			name:             "given an unregistered content type, expect error",
+			givenBody:        strings.NewReader("%PDF-1.4"),
+			givenContentType: "application/pdf",
+			expectedError:    ErrUnsupportedContentType,
+		},
+		{
+			name:             "given an invalid content type, expect error",
+			givenBody:        strings.NewReader("<html></html>"),
+			givenContentType: "text/html; charset",
+			expectedError:    ErrUnsupportedContentType,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := New().Register(&mockParser{}, "text/html")
+
+			_, err := registry.Parse(context.Background(), test.givenBody, test.givenContentType, &url.URL{Host: "example.com"})
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestRegistry_Supports(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenContentType string
+		expected         bool
+	}{
+		{
+			name:             "given a registered content type, expect supported",
+			givenContentType: "text/html; charset=utf-8",
+			expected:         true,
+		},
+		{
+			name:             "given no content type, expect supported as it will be sniffed",
+			givenContentType: "",
+			expected:         true,
+		},
+		{
+			name:             "given an unregistered content type, expect unsupported",
+			givenContentType: "image/png",
+			expected:         false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New().Register(&mockParser{}, "text/html").Supports(test.givenContentType)
+
+			if !cmp.Equal(actual, test.expected) {
+				t.Fatal(cmp.Diff(actual, test.expected))
+			}
+		})
+	}
+}
+
+func TestChain_FetchLinks(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenParsers []*mockParser
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given parsers finding overlapping URLs, expect each URL once in the order found",
+			givenParsers: []*mockParser{
+				{GivenURLs: []*url.URL{{Path: "/a/"}, {Path: "/b/"}}},
+				{GivenURLs: []*url.URL{{Path: "/b/"}, {Path: "/style.css"}}},
+			},
+			expectedURLs: []*url.URL{{Path: "/a/"}, {Path: "/b/"}, {Path: "/style.css"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var chain Chain
+			for _, parser := range test.givenParsers {
+				chain = append(chain, parser)
+			}
+
+			actual, err := chain.FetchLinks(context.Background(), strings.NewReader("<html></html>"), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+
+			for _, parser := range test.givenParsers {
+				if !cmp.Equal(parser.read, "<html></html>") {
+					t.Fatal(cmp.Diff(parser.read, "<html></html>"))
+				}
+			}
+		})
+	}
+}
+
+func TestChain_Parse(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenChain       Chain
+		expectedDocument domain.Document
+	}{
+		{
+			name: "given a document parser and a link parser, expect the metadata and all links",
+			givenChain: Chain{
+				mockDocumentParser{GivenDocument: domain.Document{
+					Links:    []*url.URL{{Path: "/a/"}},
+					Outlinks: []domain.Link{{URL: url.URL{Path: "/a/"}, Text: "A"}},
+					Metadata: domain.Metadata{Title: "First"},
+					Text:     "First page",
+				}},
+				&mockParser{GivenURLs: []*url.URL{{Path: "/style.css"}}},
+				mockDocumentParser{GivenDocument: domain.Document{
+					Metadata: domain.Metadata{Title: "Second"},
+				}},
+			},
+			expectedDocument: domain.Document{
+				Links:    []*url.URL{{Path: "/a/"}, {Path: "/style.css"}},
+				Outlinks: []domain.Link{{URL: url.URL{Path: "/a/"}, Text: "A"}},
+				Metadata: domain.Metadata{Title: "First"},
+				Text:     "First page",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := test.givenChain.Parse(context.Background(), strings.NewReader("<html></html>"), &url.URL{Host: "example.com"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedDocument) {
+				t.Fatal(cmp.Diff(actual, test.expectedDocument))
+			}
+		})
+	}
+}
+
+type mockDocumentParser struct {
+	GivenDocument domain.Document
+}
+
+func (m mockDocumentParser) FetchLinks(_ context.Context, _ io.Reader, _ *url.URL) ([]*url.URL, error) {
+	return m.GivenDocument.Links, nil
+}
+
+func (m mockDocumentParser) Parse(_ context.Context, _ io.Reader, _ *url.URL) (domain.Document, error) {
+	return m.GivenDocument, nil
+}
+
+type mockParser struct {
+	GivenURLs []*url.URL
+	read      string
+}
+
+func (m *mockParser) FetchLinks(_ context.Context, body io.Reader, _ *url.URL) ([]*url.URL, error) {
+	b, err := io.ReadAll(body)
+	m.read = string(b)
+
+	return m.GivenURLs, err
+}
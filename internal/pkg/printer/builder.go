@@ -3,12 +3,35 @@ package printer
 import (
 	"crawler/internal/domain"
 	"crawler/internal/pkg/printer/json"
+	"crawler/internal/pkg/printer/junit"
+	"crawler/internal/pkg/printer/markdown"
 	"crawler/internal/pkg/printer/raw"
+	"crawler/internal/pkg/printer/sarif"
 )
 
 // Printer instantiates the selected TypeProvider.
 type Printer struct {
 	typeSelected ContentType
+	output       string
+	markdown     []markdown.Option
+	json         []json.Option
+}
+
+// Option configures the Printer.
+type Option func(*Printer)
+
+// WithJSON configures the JSON TypeProvider with opts.
+func WithJSON(opts ...json.Option) Option {
+	return func(c *Printer) {
+		c.json = append(c.json, opts...)
+	}
+}
+
+// WithMarkdown configures the Markdown TypeProvider with opts.
+func WithMarkdown(opts ...markdown.Option) Option {
+	return func(c *Printer) {
+		c.markdown = append(c.markdown, opts...)
+	}
 }
 
 // TypeProvider provides both print and persist functionality.
@@ -21,25 +44,41 @@ type TypeProvider interface {
 type ContentType string
 
 var (
-	Raw  ContentType = "raw"
-	JSON ContentType = "json"
+	Raw      ContentType = "raw"
+	JSON     ContentType = "json"
+	JUnit    ContentType = "junit"
+	SARIF    ContentType = "sarif"
+	Markdown ContentType = "markdown"
 )
 
-// New instantiates a Printer.
-func New(typeSelected ContentType) Printer {
-	return Printer{
+// New instantiates a Printer whose TypeProvider persists to output. An empty output uses the type's default file.
+func New(typeSelected ContentType, output string, opts ...Option) Printer {
+	c := Printer{
 		typeSelected: typeSelected,
+		output:       output,
 	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
 }
 
 // Create returns the TypeProvider for the given type.
-func (c Printer) Create(pages []domain.Page) TypeProvider {
+func (c Printer) Create(report domain.Report) TypeProvider {
 	switch c.typeSelected {
 	case JSON:
-		return json.New(pages)
+		return json.New(report, c.output, c.json...)
+	case JUnit:
+		return junit.New(report, c.output)
+	case SARIF:
+		return sarif.New(report, c.output)
+	case Markdown:
+		return markdown.New(report, c.output, c.markdown...)
 	case Raw:
-		return raw.New(pages)
+		return raw.New(report, c.output)
 	default:
-		return raw.New(pages)
+		return raw.New(report, c.output)
 	}
 }
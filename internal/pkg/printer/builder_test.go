@@ -3,7 +3,10 @@ package printer
 import (
 	"crawler/internal/domain"
 	"crawler/internal/pkg/printer/json"
+	"crawler/internal/pkg/printer/junit"
+	"crawler/internal/pkg/printer/markdown"
 	"crawler/internal/pkg/printer/raw"
+	"crawler/internal/pkg/printer/sarif"
 	"testing"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -27,6 +30,21 @@ func TestPrinter_Create(t *testing.T) {
 			givenType:            JSON,
 			expectedTypeProvider: json.Printer{},
 		},
+		{
+			name:                 "given junit content type, expect junit type provider",
+			givenType:            JUnit,
+			expectedTypeProvider: junit.Printer{},
+		},
+		{
+			name:                 "given sarif content type, expect sarif type provider",
+			givenType:            SARIF,
+			expectedTypeProvider: sarif.Printer{},
+		},
+		{
+			name:                 "given markdown content type, expect markdown type provider",
+			givenType:            Markdown,
+			expectedTypeProvider: markdown.Printer{},
+		},
 		{
 			name:                 "given undefined content type, default to raw type provider",
 			givenType:            "test",
@@ -34,12 +52,15 @@ func TestPrinter_Create(t *testing.T) {
 		},
 	}
 
+	ignoreUnexported := cmpopts.IgnoreUnexported(raw.Printer{}, json.Printer{}, junit.Printer{}, sarif.Printer{},
+		markdown.Printer{})
+
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			a := New(test.givenType).Create([]domain.Page{})
+			a := New(test.givenType, "").Create(domain.Report{})
 
-			if !cmp.Equal(a, test.expectedTypeProvider, cmpopts.IgnoreUnexported(raw.Printer{}, json.Printer{})) {
-				t.Fatal(cmp.Diff(a, test.expectedTypeProvider, cmpopts.IgnoreUnexported(raw.Printer{}, json.Printer{})))
+			if !cmp.Equal(a, test.expectedTypeProvider, ignoreUnexported) {
+				t.Fatal(cmp.Diff(a, test.expectedTypeProvider, ignoreUnexported))
 			}
 		})
 	}
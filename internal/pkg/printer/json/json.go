@@ -15,31 +15,54 @@ var (
 	ErrFailedToMarshal = errors.New("failed to marshal content")
 )
 
-// Printer prints and persists in JSON for given domain.Page's.
+// DefaultOutput is the file written to when no output is given.
+const DefaultOutput = "output.json"
+
+// Printer prints and persists in JSON for a given domain.Report.
 type Printer struct {
-	content []domain.Page
+	content domain.Report
+	output  string
+	report  bool
+}
+
+// Option configures the Printer.
+type Option func(*Printer)
+
+// WithReport prints the whole api.Report, with every report derived from the pages, rather than only the list of
+// api.Page's.
+func WithReport() Option {
+	return func(c *Printer) {
+		c.report = true
+	}
 }
 
-// New instantiates a JSON Printer.
-func New(content []domain.Page) Printer {
-	return Printer{
+// New instantiates a JSON Printer which persists to output, or DefaultOutput if empty.
+func New(content domain.Report, output string, opts ...Option) Printer {
+	if output == "" {
+		output = DefaultOutput
+	}
+
+	c := Printer{
 		content: content,
+		output:  output,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
 	}
+
+	return c
 }
 
-// Print adapts the given domain.Page's to api.Page's.
+// Print adapts the pages of the given domain.Report to a list of api.Page's or, if WithReport is given, the whole
+// domain.Report to an api.Report.
 func (c Printer) Print() (string, error) {
-	presentationPages := make([]api.Page, len(c.content))
-
-	for i := range c.content {
-		presentationPages[i] = api.Page{
-			URL:       adaptPresentationURLFromDomain(c.content[i].URL),
-			Referrer:  adaptPresentationURLFromDomain(c.content[i].Referrer),
-			CrawledAt: c.content[i].CrawledAt,
-		}
+	var v interface{} = adaptPresentationPagesFromDomain(c.content.Pages)
+	if c.report {
+		v = adaptPresentationReportFromDomain(c.content)
 	}
 
-	b, err := json.MarshalIndent(presentationPages, "", "  ")
+	b, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("%v: %w", err, ErrFailedToMarshal)
 	}
@@ -49,7 +72,236 @@ func (c Printer) Print() (string, error) {
 
 // Persist creates a JSON file with the given data.
 func (c Printer) Persist(data string) error {
-	return os.WriteFile("output.json", []byte(data), 0600)
+	return os.WriteFile(c.output, []byte(data), 0600)
+}
+
+func adaptPresentationPagesFromDomain(pages []domain.Page) []api.Page {
+	presentationPages := make([]api.Page, len(pages))
+
+	for i := range pages {
+		presentationPages[i] = adaptPresentationPageFromDomain(pages[i])
+	}
+
+	return presentationPages
+}
+
+func adaptPresentationReportFromDomain(r domain.Report) api.Report {
+	orphans := make([]api.URL, len(r.Orphans))
+
+	for i := range r.Orphans {
+		orphans[i] = adaptPresentationURLFromDomain(r.Orphans[i])
+	}
+
+	findings := make([]api.Finding, len(r.Findings))
+
+	for i := range r.Findings {
+		findings[i] = adaptPresentationFindingFromDomain(r.Findings[i])
+	}
+
+	duplicates := make([]api.Duplicates, len(r.Duplicates))
+
+	for i := range r.Duplicates {
+		duplicates[i] = api.Duplicates{
+			Exact: r.Duplicates[i].Exact,
+			URLs:  adaptPresentationURLsFromDomain(r.Duplicates[i].URLs),
+		}
+	}
+
+	certificates := make([]api.Certificate, len(r.Certificates))
+
+	for i := range r.Certificates {
+		certificates[i] = adaptPresentationCertificateFromDomain(r.Certificates[i])
+	}
+
+	crawlErrors := make([]api.CrawlError, len(r.Errors))
+
+	for i := range r.Errors {
+		crawlErrors[i] = adaptPresentationCrawlErrorFromDomain(r.Errors[i])
+	}
+
+	thresholds := make([]api.Threshold, len(r.Thresholds))
+
+	for i := range r.Thresholds {
+		thresholds[i] = api.Threshold{
+			Name:     r.Thresholds[i].Name,
+			Limit:    r.Thresholds[i].Limit,
+			Actual:   r.Thresholds[i].Actual,
+			Exceeded: r.Thresholds[i].Exceeded(),
+			URLs:     adaptPresentationURLListFromDomain(r.Thresholds[i].URLs),
+			ExitCode: r.Thresholds[i].ExitCode,
+		}
+	}
+
+	return api.Report{
+		Pages:        adaptPresentationPagesFromDomain(r.Pages),
+		Orphans:      orphans,
+		Findings:     findings,
+		Duplicates:   duplicates,
+		Changes:      adaptPresentationChangesFromDomain(r.Changes),
+		Certificates: certificates,
+		Errors:       crawlErrors,
+		Thresholds:   thresholds,
+	}
+}
+
+func adaptPresentationCrawlErrorFromDomain(e domain.CrawlError) api.CrawlError {
+	var referrer *api.URL
+
+	if e.Referrer != (url.URL{}) {
+		r := adaptPresentationURLFromDomain(e.Referrer)
+		referrer = &r
+	}
+
+	var cause string
+
+	if e.Err != nil {
+		cause = e.Err.Error()
+	}
+
+	return api.CrawlError{
+		URL:      adaptPresentationURLFromDomain(e.URL),
+		Referrer: referrer,
+		Phase:    string(e.Phase),
+		Status:   e.Status,
+		Cause:    cause,
+	}
+}
+
+func adaptPresentationCertificateFromDomain(c domain.Certificate) api.Certificate {
+	return api.Certificate{
+		Host:          c.Host,
+		Subject:       c.Subject,
+		Issuer:        c.Issuer,
+		DNSNames:      c.DNSNames,
+		NotBefore:     c.NotBefore,
+		NotAfter:      c.NotAfter,
+		TLSVersion:    c.TLSVersion,
+		DaysRemaining: c.DaysRemaining,
+		Expired:       c.DaysRemaining < 0,
+	}
+}
+
+func adaptPresentationChangesFromDomain(c *domain.Changes) *api.Changes {
+	if c == nil {
+		return nil
+	}
+
+	return &api.Changes{
+		Added:     adaptPresentationURLListFromDomain(c.Added),
+		Changed:   adaptPresentationURLListFromDomain(c.Changed),
+		Unchanged: adaptPresentationURLListFromDomain(c.Unchanged),
+		Removed:   adaptPresentationURLListFromDomain(c.Removed),
+	}
+}
+
+// adaptPresentationURLListFromDomain always returns a list, so an empty section of the report is shown as empty
+// rather than null.
+func adaptPresentationURLListFromDomain(urls []url.URL) []api.URL {
+	presentation := make([]api.URL, len(urls))
+
+	for i := range urls {
+		presentation[i] = adaptPresentationURLFromDomain(urls[i])
+	}
+
+	return presentation
+}
+
+func adaptPresentationFindingFromDomain(f domain.Finding) api.Finding {
+	return api.Finding{
+		Rule:     f.Rule,
+		Severity: string(f.Severity),
+		URL:      adaptPresentationURLFromDomain(f.URL),
+		Message:  f.Message,
+	}
+}
+
+func adaptPresentationPageFromDomain(p domain.Page) api.Page {
+	return api.Page{
+		URL:          adaptPresentationURLFromDomain(p.URL),
+		Referrer:     adaptPresentationURLFromDomain(p.Referrer),
+		Seed:         adaptPresentationURLFromDomain(p.Seed),
+		Source:       string(p.Source),
+		Linked:       p.Linked,
+		Depth:        p.Depth,
+		CrawledAt:    p.CrawledAt,
+		FetchSeconds: p.FetchDuration.Seconds(),
+		StatusCode:   p.StatusCode,
+		Redirects:    adaptPresentationURLsFromDomain(p.Redirects),
+		ContentType:  p.ContentType,
+		ETag:         p.ETag,
+		LastModified: p.LastModified,
+		Unchanged:    p.Unchanged,
+		Pending:      p.Pending,
+		Truncated:    p.Truncated,
+		SkipReason:   p.SkipReason,
+		Metadata:     adaptPresentationMetadataFromDomain(p.Metadata),
+		ContentHash:  p.ContentHash,
+		SimHash:      adaptPresentationSimHashFromDomain(p.SimHash),
+		Outlinks:     adaptPresentationLinksFromDomain(p.Outlinks),
+		Links:        adaptPresentationURLsFromDomain(p.Links),
+	}
+}
+
+func adaptPresentationLinksFromDomain(links []domain.Link) []api.Link {
+	var presentation []api.Link
+
+	for _, l := range links {
+		presentation = append(presentation, api.Link{
+			URL:      adaptPresentationURLFromDomain(l.URL),
+			Text:     l.Text,
+			Title:    l.Title,
+			Rel:      l.Rel,
+			Target:   l.Target,
+			Location: string(l.Location),
+		})
+	}
+
+	return presentation
+}
+
+// adaptPresentationSimHashFromDomain shows a SimHash in hex, as JSON numbers cannot hold every uint64 precisely.
+func adaptPresentationSimHashFromDomain(simHash uint64) string {
+	if simHash == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%016x", simHash)
+}
+
+func adaptPresentationMetadataFromDomain(m domain.Metadata) api.Metadata {
+	var canonical *api.URL
+
+	if m.Canonical != (url.URL{}) {
+		u := adaptPresentationURLFromDomain(m.Canonical)
+		canonical = &u
+	}
+
+	var alternates []api.Alternate
+
+	for _, a := range m.Alternates {
+		alternates = append(alternates, api.Alternate{Lang: a.Lang, URL: adaptPresentationURLFromDomain(a.URL)})
+	}
+
+	return api.Metadata{
+		Title:       m.Title,
+		Description: m.Description,
+		Canonical:   canonical,
+		Alternates:  alternates,
+		H1:          m.H1,
+		H2:          m.H2,
+		Lang:        m.Lang,
+		WordCount:   m.WordCount,
+	}
+}
+
+func adaptPresentationURLsFromDomain(urls []url.URL) []api.URL {
+	var presentation []api.URL
+
+	for _, u := range urls {
+		presentation = append(presentation, adaptPresentationURLFromDomain(u))
+	}
+
+	return presentation
 }
 
 func adaptPresentationURLFromDomain(u url.URL) api.URL {
@@ -4,7 +4,9 @@ import (
 	"crawler/api"
 	"crawler/internal/domain"
 	"encoding/json"
+	"errors"
 	"net/url"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -14,22 +16,26 @@ import (
 func TestContentTypeJSON_Print_Success(t *testing.T) {
 	tests := []struct {
 		name         string
-		givenContent []domain.Page
+		givenContent domain.Report
 		expected     []api.Page
 	}{
 		{
-			name: "given pages, expect JSON output",
-			givenContent: []domain.Page{
-				{
-					URL: url.URL{
-						Host: "example.com",
-						Path: "/test/",
+			name: "given a report, expect JSON output of only its pages",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL: url.URL{
+							Host: "example.com",
+							Path: "/test/",
+						},
+						Referrer: url.URL{
+							Host: "example.com",
+						},
+						CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 					},
-					Referrer: url.URL{
-						Host: "example.com",
-					},
-					CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 				},
+				Orphans:  []url.URL{{Host: "example.com", Path: "/orphan/"}},
+				Findings: []domain.Finding{{Rule: "missing-title", URL: url.URL{Host: "example.com", Path: "/test/"}}},
 			},
 			expected: []api.Page{
 				{
@@ -44,11 +50,16 @@ func TestContentTypeJSON_Print_Success(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "given an empty report, expect an empty list",
+			givenContent: domain.Report{},
+			expected:     []api.Page{},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := New(test.givenContent).Print()
+			actual, err := New(test.givenContent, "").Print()
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -67,20 +78,321 @@ func TestContentTypeJSON_Print_Success(t *testing.T) {
 	}
 }
 
-func TestContentTypeJSON_Persist_Success(t *testing.T) {
+func TestContentTypeJSON_Print_Report(t *testing.T) {
 	tests := []struct {
 		name         string
-		givenContent []domain.Page
-		expected     string
+		givenContent domain.Report
+		expected     api.Report
 	}{
 		{
-			name: "given pages, expect to be persisted",
-			givenContent: []domain.Page{
-				{
-					Referrer: url.URL{
+			name: "given a report, expect JSON output",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL: url.URL{
+							Host: "example.com",
+							Path: "/test/",
+						},
+						Referrer: url.URL{
+							Host: "example.com",
+						},
+						Source:        domain.SourceSitemap,
+						CrawledAt:     time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
+						FetchDuration: 1500 * time.Millisecond,
+					},
+				},
+				Orphans: []url.URL{
+					{
 						Host: "example.com",
+						Path: "/test/",
+					},
+				},
+				Findings: []domain.Finding{
+					{
+						Rule:     "missing-title",
+						Severity: domain.SeverityError,
+						URL:      url.URL{Host: "example.com", Path: "/test/"},
+						Message:  "page has no title",
+					},
+				},
+				Duplicates: []domain.Duplicates{
+					{
+						Exact: true,
+						URLs:  []url.URL{{Host: "example.com", Path: "/test/"}, {Host: "example.com", Path: "/copy/"}},
+					},
+				},
+				Certificates: []domain.Certificate{
+					{
+						Host:          "example.com",
+						Subject:       "CN=example.com",
+						Issuer:        "CN=Example CA",
+						NotAfter:      time.Date(2021, 06, 01, 00, 00, 00, 00, time.UTC),
+						TLSVersion:    "TLS 1.3",
+						DaysRemaining: -10,
+					},
+				},
+			},
+			expected: api.Report{
+				Pages: []api.Page{
+					{
+						URL: api.URL{
+							Host: "example.com",
+							Path: "/test/",
+						},
+						Referrer: api.URL{
+							Host: "example.com",
+						},
+						Source:       "sitemap",
+						CrawledAt:    time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
+						FetchSeconds: 1.5,
+					},
+				},
+				Orphans: []api.URL{
+					{
+						Host: "example.com",
+						Path: "/test/",
+					},
+				},
+				Findings: []api.Finding{
+					{
+						Rule:     "missing-title",
+						Severity: "error",
+						URL:      api.URL{Host: "example.com", Path: "/test/"},
+						Message:  "page has no title",
+					},
+				},
+				Duplicates: []api.Duplicates{
+					{
+						Exact: true,
+						URLs:  []api.URL{{Host: "example.com", Path: "/test/"}, {Host: "example.com", Path: "/copy/"}},
+					},
+				},
+				Certificates: []api.Certificate{
+					{
+						Host:          "example.com",
+						Subject:       "CN=example.com",
+						Issuer:        "CN=Example CA",
+						NotAfter:      time.Date(2021, 06, 01, 00, 00, 00, 00, time.UTC),
+						TLSVersion:    "TLS 1.3",
+						DaysRemaining: -10,
+						Expired:       true,
+					},
+				},
+				Errors:     []api.CrawlError{},
+				Thresholds: []api.Threshold{},
+			},
+		},
+		{
+			name: "given a page with redirects and metadata, expect them in the JSON output",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL:       url.URL{Host: "example.com", Path: "/test/"},
+						Redirects: []url.URL{{Host: "example.com", Path: "/moved/"}},
+						Metadata: domain.Metadata{
+							Title:     "Test",
+							Canonical: url.URL{Host: "example.com", Path: "/test/"},
+							Alternates: []domain.Alternate{
+								{Lang: "fr", URL: url.URL{Host: "example.fr", Path: "/test/"}},
+							},
+							H1:        []string{"Test"},
+							Lang:      "en",
+							WordCount: 12,
+						},
+						ContentHash: "abc123",
+						SimHash:     0xff,
+						Outlinks: []domain.Link{
+							{
+								URL:      url.URL{Host: "example.com", Path: "/moved/"},
+								Text:     "Click here",
+								Rel:      []string{"nofollow"},
+								Location: domain.LocationFooter,
+							},
+						},
+					},
+				},
+			},
+			expected: api.Report{
+				Pages: []api.Page{
+					{
+						URL:       api.URL{Host: "example.com", Path: "/test/"},
+						Redirects: []api.URL{{Host: "example.com", Path: "/moved/"}},
+						Metadata: api.Metadata{
+							Title:     "Test",
+							Canonical: &api.URL{Host: "example.com", Path: "/test/"},
+							Alternates: []api.Alternate{
+								{Lang: "fr", URL: api.URL{Host: "example.fr", Path: "/test/"}},
+							},
+							H1:        []string{"Test"},
+							Lang:      "en",
+							WordCount: 12,
+						},
+						ContentHash: "abc123",
+						SimHash:     "00000000000000ff",
+						Outlinks: []api.Link{
+							{
+								URL:      api.URL{Host: "example.com", Path: "/moved/"},
+								Text:     "Click here",
+								Rel:      []string{"nofollow"},
+								Location: "footer",
+							},
+						},
+					},
+				},
+				Orphans:      []api.URL{},
+				Findings:     []api.Finding{},
+				Duplicates:   []api.Duplicates{},
+				Certificates: []api.Certificate{},
+				Errors:       []api.CrawlError{},
+				Thresholds:   []api.Threshold{},
+			},
+		},
+		{
+			name: "given a report compared with a previous crawl, expect the changes in the JSON output",
+			givenContent: domain.Report{
+				Changes: &domain.Changes{
+					Changed: []url.URL{{Host: "example.com", Path: "/edited/"}},
+					Removed: []url.URL{{Host: "example.com", Path: "/gone/"}},
+				},
+			},
+			expected: api.Report{
+				Pages:        []api.Page{},
+				Orphans:      []api.URL{},
+				Findings:     []api.Finding{},
+				Duplicates:   []api.Duplicates{},
+				Certificates: []api.Certificate{},
+				Errors:       []api.CrawlError{},
+				Thresholds:   []api.Threshold{},
+				Changes: &api.Changes{
+					Added:     []api.URL{},
+					Changed:   []api.URL{{Host: "example.com", Path: "/edited/"}},
+					Unchanged: []api.URL{},
+					Removed:   []api.URL{{Host: "example.com", Path: "/gone/"}},
+				},
+			},
+		},
+		{
+			name: "given a report with crawl errors, expect the errors in the JSON output",
+			givenContent: domain.Report{
+				Errors: []domain.CrawlError{
+					{
+						URL:      url.URL{Host: "example.com", Path: "/missing/"},
+						Referrer: url.URL{Host: "example.com"},
+						Phase:    domain.PhaseFetch,
+						Status:   404,
+						Err:      errors.New("404: invalid status code received"),
+					},
+					{
+						URL:   url.URL{Host: "example.com"},
+						Phase: domain.PhaseParse,
+						Err:   errors.New("failed to parse html"),
+					},
+				},
+			},
+			expected: api.Report{
+				Pages:        []api.Page{},
+				Orphans:      []api.URL{},
+				Findings:     []api.Finding{},
+				Duplicates:   []api.Duplicates{},
+				Certificates: []api.Certificate{},
+				Errors: []api.CrawlError{
+					{
+						URL:      api.URL{Host: "example.com", Path: "/missing/"},
+						Referrer: &api.URL{Host: "example.com"},
+						Phase:    "fetch",
+						Status:   404,
+						Cause:    "404: invalid status code received",
+					},
+					{
+						URL:   api.URL{Host: "example.com"},
+						Phase: "parse",
+						Cause: "failed to parse html",
+					},
+				},
+				Thresholds: []api.Threshold{},
+			},
+		},
+		{
+			name: "given a report with thresholds, expect whether each was exceeded in the JSON output",
+			givenContent: domain.Report{
+				Thresholds: []domain.Threshold{
+					{
+						Name:     "broken-links",
+						Limit:    0,
+						Actual:   1,
+						URLs:     []url.URL{{Host: "example.com", Path: "/missing/"}},
+						ExitCode: 2,
+					},
+					{
+						Name:     "server-errors",
+						Limit:    0,
+						ExitCode: 3,
+					},
+				},
+			},
+			expected: api.Report{
+				Pages:        []api.Page{},
+				Orphans:      []api.URL{},
+				Findings:     []api.Finding{},
+				Duplicates:   []api.Duplicates{},
+				Certificates: []api.Certificate{},
+				Errors:       []api.CrawlError{},
+				Thresholds: []api.Threshold{
+					{
+						Name:     "broken-links",
+						Limit:    0,
+						Actual:   1,
+						Exceeded: true,
+						URLs:     []api.URL{{Host: "example.com", Path: "/missing/"}},
+						ExitCode: 2,
+					},
+					{
+						Name:     "server-errors",
+						Limit:    0,
+						URLs:     []api.URL{},
+						ExitCode: 3,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(test.givenContent, "", WithReport()).Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var a api.Report
+
+			err = json.Unmarshal([]byte(actual), &a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(a, test.expected) {
+				t.Fatal(cmp.Diff(a, test.expected))
+			}
+		})
+	}
+}
+
+func TestContentTypeJSON_Persist_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+	}{
+		{
+			name: "given a report, expect to be persisted",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						Referrer: url.URL{
+							Host: "example.com",
+						},
+						CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 					},
-					CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 				},
 			},
 		},
@@ -88,7 +400,7 @@ func TestContentTypeJSON_Persist_Success(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			printer := New(test.givenContent)
+			printer := New(test.givenContent, filepath.Join(t.TempDir(), "output.json"))
 
 			content, err := printer.Print()
 			if err != nil {
@@ -0,0 +1,285 @@
+package junit
+
+import (
+	"crawler/internal/domain"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrFailedToMarshal is returned if the marshaller fails.
+var (
+	ErrFailedToMarshal = errors.New("failed to marshal content")
+)
+
+// DefaultOutput is the file written to when no output is given.
+const DefaultOutput = "junit.xml"
+
+// suiteThresholds is the testsuite of the thresholds checked.
+const suiteThresholds = "thresholds"
+
+// Printer prints and persists in JUnit XML for a given domain.Report, so CI systems can show what failed. Every page
+// crawled is a testcase of the testsuite of its host, and every threshold checked a testcase of the thresholds
+// testsuite.
+type Printer struct {
+	content domain.Report
+	output  string
+}
+
+// New instantiates a JUnit Printer which persists to output, or DefaultOutput if empty.
+func New(content domain.Report, output string) Printer {
+	if output == "" {
+		output = DefaultOutput
+	}
+
+	return Printer{
+		content: content,
+		output:  output,
+	}
+}
+
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Time     string     `xml:"time,attr,omitempty"`
+	Cases    []testCase `xml:"testcase"`
+}
+
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr,omitempty"`
+	Failure   *failure `xml:"failure,omitempty"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// problem is why a page's testcase failed.
+type problem struct {
+	kind    string
+	message string
+	// crawlError is whether the problem is a domain.CrawlError rather than a finding.
+	crawlError bool
+}
+
+// Print shows every page crawled as a testcase, which fails if the page did not respond with a 2xx status, could not
+// be crawled or broke an audit rule. The time of each testcase is how long the page took to fetch.
+func (c Printer) Print() (string, error) {
+	suites := testSuites{
+		Name:   "crawler",
+		Suites: c.hostSuites(),
+	}
+
+	if len(c.content.Thresholds) > 0 {
+		suites.Suites = append(suites.Suites, thresholdSuite(c.content.Thresholds))
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+	}
+
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, ErrFailedToMarshal)
+	}
+
+	return xml.Header + string(b), nil
+}
+
+// Persist creates a JUnit XML file with the given data.
+func (c Printer) Persist(data string) error {
+	return os.WriteFile(c.output, []byte(data), 0600)
+}
+
+// hostSuites groups the testcases of the pages, and of any URLs which could not be crawled, by host.
+func (c Printer) hostSuites() []testSuite {
+	problems := c.problems()
+	suites := make(map[string]*testSuite)
+	durations := make(map[string]float64)
+
+	var hosts []string
+
+	add := func(u url.URL, seconds float64, tc testCase) {
+		suite, ok := suites[u.Host]
+		if !ok {
+			suite = &testSuite{Name: u.Host}
+			suites[u.Host] = suite
+			hosts = append(hosts, u.Host)
+		}
+
+		suite.Tests++
+		durations[u.Host] += seconds
+
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, page := range c.content.Pages {
+		seconds := page.FetchDuration.Seconds()
+
+		add(page.URL, seconds, testCase{
+			Name:      page.URL.String(),
+			ClassName: page.URL.Host,
+			Time:      formatSeconds(seconds),
+			Failure:   newFailure(pageProblems(page, problems[page.URL])),
+		})
+
+		delete(problems, page.URL)
+	}
+
+	for i := range c.content.Errors {
+		u := c.content.Errors[i].URL
+
+		if pp, ok := problems[u]; ok {
+			add(u, 0, testCase{
+				Name:      u.String(),
+				ClassName: u.Host,
+				Failure:   newFailure(pp),
+			})
+
+			delete(problems, u)
+		}
+	}
+
+	sort.Strings(hosts)
+
+	ordered := make([]testSuite, len(hosts))
+	for i, host := range hosts {
+		suites[host].Time = formatSeconds(durations[host])
+		ordered[i] = *suites[host]
+	}
+
+	return ordered
+}
+
+// problems finds the crawl errors and findings of every URL.
+func (c Printer) problems() map[url.URL][]problem {
+	problems := make(map[url.URL][]problem)
+
+	for i := range c.content.Errors {
+		e := c.content.Errors[i]
+
+		problems[e.URL] = append(problems[e.URL], problem{
+			kind:       string(e.Phase),
+			message:    e.Error(),
+			crawlError: true,
+		})
+	}
+
+	for _, f := range c.content.Findings {
+		problems[f.URL] = append(problems[f.URL], problem{
+			kind:    f.Rule,
+			message: f.Message,
+		})
+	}
+
+	return problems
+}
+
+// pageProblems adds a problem for a response without a 2xx status to the other problems of the page, unless a crawl
+// error already reports it.
+func pageProblems(page domain.Page, problems []problem) []problem {
+	ok := page.StatusCode >= http.StatusOK && page.StatusCode < http.StatusMultipleChoices
+	if ok || page.StatusCode == 0 || hasCrawlError(problems) {
+		return problems
+	}
+
+	return append([]problem{{
+		kind:    "status",
+		message: fmt.Sprintf("responded with status %v", page.StatusCode),
+	}}, problems...)
+}
+
+func hasCrawlError(problems []problem) bool {
+	for _, p := range problems {
+		if p.crawlError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newFailure describes the first problem in the failure's message, and every problem in its text. It returns nil if
+// there are no problems.
+func newFailure(problems []problem) *failure {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(problems))
+	for i, p := range problems {
+		lines[i] = fmt.Sprintf("%v: %v", p.kind, p.message)
+	}
+
+	return &failure{
+		Message: problems[0].message,
+		Type:    problems[0].kind,
+		Text:    strings.Join(lines, "\n"),
+	}
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+func thresholdSuite(thresholds []domain.Threshold) testSuite {
+	suite := testSuite{
+		Name:  suiteThresholds,
+		Tests: len(thresholds),
+	}
+
+	for _, t := range thresholds {
+		tc := testCase{
+			Name:      t.Name,
+			ClassName: suiteThresholds,
+		}
+
+		if t.Exceeded() {
+			suite.Failures++
+
+			tc.Failure = &failure{
+				Message: t.String(),
+				Type:    t.Name,
+				Text:    joinURLs(t),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	return suite
+}
+
+// joinURLs lists the pages with the threshold's problem, one per line.
+func joinURLs(t domain.Threshold) string {
+	urls := make([]string, len(t.URLs))
+
+	for i := range t.URLs {
+		urls[i] = t.URLs[i].String()
+	}
+
+	return strings.Join(urls, "\n")
+}
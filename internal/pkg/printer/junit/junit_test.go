@@ -0,0 +1,163 @@
+package junit
+
+import (
+	"crawler/internal/domain"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrinter_Print_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+		expected     string
+	}{
+		{
+			name: "given pages of two hosts, expect a testsuite per host with a failing testcase for each broken page",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL:           url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+						FetchDuration: 120 * time.Millisecond,
+						StatusCode:    200,
+					},
+					{
+						URL:           url.URL{Scheme: "https", Host: "example.com", Path: "/missing/"},
+						FetchDuration: 30 * time.Millisecond,
+						StatusCode:    404,
+					},
+					{
+						URL:           url.URL{Scheme: "https", Host: "blog.example.com", Path: "/"},
+						FetchDuration: 2 * time.Second,
+						StatusCode:    503,
+					},
+					{
+						URL:           url.URL{Scheme: "https", Host: "example.com", Path: "/untitled/"},
+						FetchDuration: 50 * time.Millisecond,
+						StatusCode:    200,
+					},
+				},
+				Errors: []domain.CrawlError{
+					{
+						URL:    url.URL{Scheme: "https", Host: "example.com", Path: "/missing/"},
+						Phase:  domain.PhaseFetch,
+						Status: 404,
+						Err:    errors.New("404: invalid status code received"),
+					},
+				},
+				Findings: []domain.Finding{
+					{
+						Rule:     "missing-title",
+						Severity: domain.SeverityError,
+						URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/untitled/"},
+						Message:  "page has no title",
+					},
+				},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="crawler" tests="4" failures="3">
+  <testsuite name="blog.example.com" tests="1" failures="1" time="2.000">
+    <testcase name="https://blog.example.com/" classname="blog.example.com" time="2.000">
+      <failure message="responded with status 503" type="status">status: responded with status 503</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="example.com" tests="3" failures="2" time="0.200">
+    <testcase name="https://example.com/" classname="example.com" time="0.120"></testcase>
+    <testcase name="https://example.com/missing/" classname="example.com" time="0.030">
+      <failure message="fetch https://example.com/missing/: 404: invalid status code received" type="fetch">` +
+				`fetch: fetch https://example.com/missing/: 404: invalid status code received</failure>
+    </testcase>
+    <testcase name="https://example.com/untitled/" classname="example.com" time="0.050">
+      <failure message="page has no title" type="missing-title">missing-title: page has no title</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`,
+		},
+		{
+			name: "given thresholds, expect a failing testcase for each exceeded threshold",
+			givenContent: domain.Report{
+				Thresholds: []domain.Threshold{
+					{
+						Name:   "broken-links",
+						Limit:  0,
+						Actual: 2,
+						URLs: []url.URL{
+							{Scheme: "https", Host: "example.com", Path: "/missing/"},
+							{Scheme: "https", Host: "example.com", Path: "/gone/"},
+						},
+						ExitCode: 2,
+					},
+					{
+						Name:     "server-errors",
+						Limit:    0,
+						ExitCode: 3,
+					},
+				},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="crawler" tests="2" failures="1">
+  <testsuite name="thresholds" tests="2" failures="1">
+    <testcase name="broken-links" classname="thresholds">
+      <failure message="broken-links is 2, more than the 0 allowed" type="broken-links">` +
+				`https://example.com/missing/&#xA;https://example.com/gone/</failure>
+    </testcase>
+    <testcase name="server-errors" classname="thresholds"></testcase>
+  </testsuite>
+</testsuites>`,
+		},
+		{
+			name:         "given no thresholds, expect no testsuite",
+			givenContent: domain.Report{},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="crawler" tests="0" failures="0"></testsuites>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(test.givenContent, "").Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expected) {
+				t.Fatal(cmp.Diff(actual, test.expected))
+			}
+		})
+	}
+}
+
+func TestPrinter_Persist_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+	}{
+		{
+			name: "given a report, expect to be persisted",
+			givenContent: domain.Report{
+				Thresholds: []domain.Threshold{{Name: "broken-links"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			printer := New(test.givenContent, filepath.Join(t.TempDir(), "junit.xml"))
+
+			content, err := printer.Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = printer.Persist(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package markdown
+
+import (
+	"crawler/internal/domain"
+	"crawler/internal/pkg/threshold"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultOutput is the file written to when no output is given.
+const DefaultOutput = "output.md"
+
+const (
+	// DefaultMaxSize is the most bytes printed by default, the size of the largest GitHub pull request comment.
+	DefaultMaxSize = 65536
+	// DefaultSlowThreshold is how long a page may take to fetch by default before it is reported as slow.
+	DefaultSlowThreshold = time.Second
+)
+
+const (
+	detailsEnd    = "\n</details>\n"
+	truncatedNote = "\n_Some rows were left out to fit the size limit, see the full report for the rest._\n"
+	// moreReserve is the space left for the line counting the rows of a section which were left out.
+	moreReserve = 64
+)
+
+// Printer prints and persists in Markdown for a given domain.Report, as a summary which fits in a pull request comment.
+type Printer struct {
+	content       domain.Report
+	output        string
+	maxSize       int
+	slowThreshold time.Duration
+}
+
+// Option configures the Printer.
+type Option func(*Printer)
+
+// WithMaxSize sets the most bytes printed. Rows of the collapsible sections are left out to fit, but the summary tables
+// are always printed. 0 is unlimited.
+func WithMaxSize(maxSize int) Option {
+	return func(c *Printer) {
+		c.maxSize = maxSize
+	}
+}
+
+// WithSlowThreshold sets how long a page may take to fetch before it is reported as slow.
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(c *Printer) {
+		c.slowThreshold = threshold
+	}
+}
+
+// New instantiates a Markdown Printer which persists to output, or DefaultOutput if empty.
+func New(content domain.Report, output string, opts ...Option) Printer {
+	if output == "" {
+		output = DefaultOutput
+	}
+
+	c := Printer{
+		content:       content,
+		output:        output,
+		maxSize:       DefaultMaxSize,
+		slowThreshold: DefaultSlowThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// section is a collapsible table of the pages with one kind of problem.
+type section struct {
+	title   string
+	columns []string
+	rows    [][]string
+}
+
+// Print summarises the domain.Report with tables of the totals, the pages of each status and any thresholds checked,
+// followed by collapsible sections for broken links, redirects and slow pages.
+func (c Printer) Print() (string, error) {
+	sections := []section{c.brokenLinks(), c.redirects(), c.slowPages()}
+
+	var b strings.Builder
+
+	b.WriteString("# Crawl summary\n\n")
+	writeTable(&b, []string{"Pages", "Broken links", "Redirects", "Slow pages", "Errors", "Findings"}, [][]string{{
+		fmt.Sprint(len(c.content.Pages)),
+		fmt.Sprint(len(sections[0].rows)),
+		fmt.Sprint(len(sections[1].rows)),
+		fmt.Sprint(len(sections[2].rows)),
+		fmt.Sprint(len(c.content.Errors)),
+		fmt.Sprint(len(c.content.Findings)),
+	}})
+
+	b.WriteString("\n")
+	writeTable(&b, []string{"Status", "Pages"}, c.statuses())
+
+	if len(c.content.Thresholds) > 0 {
+		b.WriteString("\n")
+		writeTable(&b, []string{"Threshold", "Limit", "Actual", "Result"}, c.thresholds())
+	}
+
+	truncated := false
+
+	for _, s := range sections {
+		if len(s.rows) == 0 {
+			continue
+		}
+
+		if !c.writeSection(&b, s) {
+			truncated = true
+		}
+	}
+
+	if truncated {
+		b.WriteString(truncatedNote)
+	}
+
+	return b.String(), nil
+}
+
+// Persist creates a Markdown file with the given data.
+func (c Printer) Persist(data string) error {
+	return os.WriteFile(c.output, []byte(data), 0600)
+}
+
+// writeSection writes as many rows of the section as fit in the maximum size, and reports whether all of them did.
+func (c Printer) writeSection(b *strings.Builder, s section) bool {
+	var head strings.Builder
+
+	fmt.Fprintf(&head, "\n<details>\n<summary>%v (%v)</summary>\n\n", s.title, len(s.rows))
+	writeTable(&head, s.columns, nil)
+
+	if !c.fits(b, head.String()) {
+		return false
+	}
+
+	b.WriteString(head.String())
+
+	shown := 0
+
+	for _, row := range s.rows {
+		var line strings.Builder
+
+		writeRow(&line, row)
+
+		if !c.fits(b, line.String()) {
+			break
+		}
+
+		b.WriteString(line.String())
+		shown++
+	}
+
+	if shown < len(s.rows) {
+		fmt.Fprintf(b, "\n%v more not shown.\n", len(s.rows)-shown)
+	}
+
+	b.WriteString(detailsEnd)
+
+	return shown == len(s.rows)
+}
+
+// fits reports whether s can be written, leaving space to close the section and note that rows were left out.
+func (c Printer) fits(b *strings.Builder, s string) bool {
+	if c.maxSize <= 0 {
+		return true
+	}
+
+	return b.Len()+len(s)+len(detailsEnd)+moreReserve+len(truncatedNote) <= c.maxSize
+}
+
+// statuses counts the pages of each status code, in ascending order, followed by the Pending pages.
+func (c Printer) statuses() [][]string {
+	counts := make(map[int]int)
+	pending := 0
+
+	for _, page := range c.content.Pages {
+		if page.Pending {
+			pending++
+
+			continue
+		}
+
+		counts[page.StatusCode]++
+	}
+
+	codes := make([]int, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	rows := make([][]string, len(codes))
+	for i, code := range codes {
+		rows[i] = []string{status(code), fmt.Sprint(counts[code])}
+	}
+
+	if pending > 0 {
+		rows = append(rows, []string{"not fetched", fmt.Sprint(pending)})
+	}
+
+	return rows
+}
+
+func (c Printer) thresholds() [][]string {
+	rows := make([][]string, len(c.content.Thresholds))
+
+	for i, t := range c.content.Thresholds {
+		result := "passed"
+		if t.Exceeded() {
+			result = "**exceeded**"
+		}
+
+		rows[i] = []string{t.Name, fmt.Sprint(t.Limit), fmt.Sprint(t.Actual), result}
+	}
+
+	return rows
+}
+
+// brokenLinks lists the URLs the broken-links threshold counts, along with every page linking to them.
+func (c Printer) brokenLinks() section {
+	s := section{title: "Broken links", columns: []string{"URL", "Status", "Linked from"}}
+	referrers := c.referrers()
+	responses := c.responses()
+
+	for _, u := range threshold.Broken(c.content) {
+		res := responses[u]
+
+		linkedFrom := referrers[u]
+		if len(linkedFrom) == 0 && res.referrer != (url.URL{}) {
+			linkedFrom = []string{res.referrer.String()}
+		}
+
+		s.rows = append(s.rows, []string{u.String(), status(res.status), strings.Join(linkedFrom, "<br>")})
+	}
+
+	return s
+}
+
+// response is what is known of how a URL responded, and where it was found.
+type response struct {
+	status   int
+	referrer url.URL
+}
+
+// responses finds the status and referrer of every page, and of every URL which could not be crawled.
+func (c Printer) responses() map[url.URL]response {
+	responses := make(map[url.URL]response)
+
+	for i := range c.content.Errors {
+		e := c.content.Errors[i]
+		responses[e.URL] = response{status: e.Status, referrer: e.Referrer}
+	}
+
+	for _, page := range c.content.Pages {
+		responses[page.URL] = response{status: page.StatusCode, referrer: page.Referrer}
+	}
+
+	return responses
+}
+
+// referrers finds the pages which link to each URL.
+func (c Printer) referrers() map[url.URL][]string {
+	referrers := make(map[url.URL][]string)
+
+	for _, page := range c.content.Pages {
+		for _, link := range page.Links {
+			referrers[link] = append(referrers[link], page.URL.String())
+		}
+	}
+
+	return referrers
+}
+
+// redirects lists the pages which were redirected, with every URL they were redirected to.
+func (c Printer) redirects() section {
+	s := section{title: "Redirects", columns: []string{"URL", "Redirected to"}}
+
+	for _, page := range c.content.Pages {
+		if len(page.Redirects) == 0 {
+			continue
+		}
+
+		chain := make([]string, len(page.Redirects))
+		for i := range page.Redirects {
+			chain[i] = page.Redirects[i].String()
+		}
+
+		s.rows = append(s.rows, []string{page.URL.String(), strings.Join(chain, " → ")})
+	}
+
+	return s
+}
+
+// slowPages lists the pages which took at least the slow threshold to fetch, slowest first.
+func (c Printer) slowPages() section {
+	s := section{title: "Slow pages", columns: []string{"URL", "Fetch time"}}
+
+	var slow []domain.Page
+
+	for _, page := range c.content.Pages {
+		if page.FetchDuration >= c.slowThreshold {
+			slow = append(slow, page)
+		}
+	}
+
+	sort.SliceStable(slow, func(i, j int) bool {
+		return slow[i].FetchDuration > slow[j].FetchDuration
+	})
+
+	for _, page := range slow {
+		s.rows = append(s.rows, []string{page.URL.String(), page.FetchDuration.Round(time.Millisecond).String()})
+	}
+
+	return s
+}
+
+func writeTable(b *strings.Builder, columns []string, rows [][]string) {
+	writeRow(b, columns)
+
+	separators := make([]string, len(columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+
+	writeRow(b, separators)
+
+	for _, row := range rows {
+		writeRow(b, row)
+	}
+}
+
+// writeRow writes the cells of a table row, escaping any pipes in them.
+func writeRow(b *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+
+	fmt.Fprintf(b, "| %v |\n", strings.Join(escaped, " | "))
+}
+
+// status describes a status code, which is 0 if the page could not be fetched at all.
+func status(code int) string {
+	if code == 0 {
+		return "no response"
+	}
+
+	return fmt.Sprint(code)
+}
@@ -0,0 +1,191 @@
+package markdown
+
+import (
+	"crawler/internal/domain"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrinter_Print_Success(t *testing.T) {
+	home := url.URL{Scheme: "https", Host: "example.com", Path: "/"}
+	about := url.URL{Scheme: "https", Host: "example.com", Path: "/about/"}
+	missing := url.URL{Scheme: "https", Host: "example.com", Path: "/missing/"}
+	moved := url.URL{Scheme: "https", Host: "example.com", Path: "/moved/"}
+	gone := url.URL{Scheme: "https", Host: "example.com", Path: "/gone/"}
+	queued := url.URL{Scheme: "https", Host: "example.com", Path: "/queued/"}
+
+	report := domain.Report{
+		Pages: []domain.Page{
+			{URL: home, StatusCode: 200, FetchDuration: 120 * time.Millisecond, Links: []url.URL{about, missing, moved}},
+			{URL: about, Referrer: home, StatusCode: 200, FetchDuration: 1500 * time.Millisecond, Links: []url.URL{missing}},
+			{URL: missing, Referrer: home, StatusCode: 404, FetchDuration: 30 * time.Millisecond},
+			{URL: moved, Referrer: home, StatusCode: 200, FetchDuration: 2 * time.Second, Redirects: []url.URL{about}},
+			{URL: gone, Referrer: about},
+			{URL: queued, Referrer: about, Pending: true},
+		},
+		Errors: []domain.CrawlError{
+			{URL: gone, Referrer: about, Phase: domain.PhaseFetch, Err: errors.New("connection refused")},
+		},
+		Findings: []domain.Finding{{Rule: "missing-title", URL: home}},
+		Thresholds: []domain.Threshold{
+			{Name: "broken-links", Limit: 0, Actual: 2},
+			{Name: "server-errors", Limit: 0},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+		givenOptions []Option
+		expected     string
+	}{
+		{
+			name:         "given a report, expect a summary with collapsible sections",
+			givenContent: report,
+			expected: `# Crawl summary
+
+| Pages | Broken links | Redirects | Slow pages | Errors | Findings |
+| --- | --- | --- | --- | --- | --- |
+| 6 | 2 | 1 | 2 | 1 | 1 |
+
+| Status | Pages |
+| --- | --- |
+| no response | 1 |
+| 200 | 3 |
+| 404 | 1 |
+| not fetched | 1 |
+
+| Threshold | Limit | Actual | Result |
+| --- | --- | --- | --- |
+| broken-links | 0 | 2 | **exceeded** |
+| server-errors | 0 | 0 | passed |
+
+<details>
+<summary>Broken links (2)</summary>
+
+| URL | Status | Linked from |
+| --- | --- | --- |
+| https://example.com/gone/ | no response | https://example.com/about/ |
+| https://example.com/missing/ | 404 | https://example.com/<br>https://example.com/about/ |
+
+</details>
+
+<details>
+<summary>Redirects (1)</summary>
+
+| URL | Redirected to |
+| --- | --- |
+| https://example.com/moved/ | https://example.com/about/ |
+
+</details>
+
+<details>
+<summary>Slow pages (2)</summary>
+
+| URL | Fetch time |
+| --- | --- |
+| https://example.com/moved/ | 2s |
+| https://example.com/about/ | 1.5s |
+
+</details>
+`,
+		},
+		{
+			name:         "given a maximum size, expect the rows which do not fit to be left out",
+			givenContent: report,
+			givenOptions: []Option{WithMaxSize(750), WithSlowThreshold(5 * time.Second)},
+			expected: `# Crawl summary
+
+| Pages | Broken links | Redirects | Slow pages | Errors | Findings |
+| --- | --- | --- | --- | --- | --- |
+| 6 | 2 | 1 | 0 | 1 | 1 |
+
+| Status | Pages |
+| --- | --- |
+| no response | 1 |
+| 200 | 3 |
+| 404 | 1 |
+| not fetched | 1 |
+
+| Threshold | Limit | Actual | Result |
+| --- | --- | --- | --- |
+| broken-links | 0 | 2 | **exceeded** |
+| server-errors | 0 | 0 | passed |
+
+<details>
+<summary>Broken links (2)</summary>
+
+| URL | Status | Linked from |
+| --- | --- | --- |
+| https://example.com/gone/ | no response | https://example.com/about/ |
+
+1 more not shown.
+
+</details>
+
+_Some rows were left out to fit the size limit, see the full report for the rest._
+`,
+		},
+		{
+			name:         "given an empty report, expect only the summary",
+			givenContent: domain.Report{},
+			expected: `# Crawl summary
+
+| Pages | Broken links | Redirects | Slow pages | Errors | Findings |
+| --- | --- | --- | --- | --- | --- |
+| 0 | 0 | 0 | 0 | 0 | 0 |
+
+| Status | Pages |
+| --- | --- |
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(test.givenContent, "", test.givenOptions...).Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expected) {
+				t.Fatal(cmp.Diff(actual, test.expected))
+			}
+		})
+	}
+}
+
+func TestPrinter_Persist_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+	}{
+		{
+			name: "given a report, expect to be persisted",
+			givenContent: domain.Report{
+				Pages: []domain.Page{{URL: url.URL{Host: "example.com"}, StatusCode: 200}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			printer := New(test.givenContent, filepath.Join(t.TempDir(), "output.md"))
+
+			content, err := printer.Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = printer.Persist(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
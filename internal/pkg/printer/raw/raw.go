@@ -3,27 +3,217 @@ package raw
 import (
 	"crawler/internal/domain"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 )
 
-// Printer prints and persists in ASCII for given domain.Page's.
+// DefaultOutput is the file written to when no output is given.
+const DefaultOutput = "output.txt"
+
+// dateFormat is how the expiry dates of certificates are printed.
+const dateFormat = "2006-01-02"
+
+// Printer prints and persists in ASCII for a given domain.Report.
 type Printer struct {
-	content []domain.Page
+	content domain.Report
+	output  string
 }
 
-// New instantiates a Raw Printer.
-func New(content []domain.Page) Printer {
+// New instantiates a Raw Printer which persists to output, or DefaultOutput if empty.
+func New(content domain.Report, output string) Printer {
+	if output == "" {
+		output = DefaultOutput
+	}
+
 	return Printer{
 		content: content,
+		output:  output,
 	}
 }
 
-// Print prints the given domain.Page's as ASCII.
+// section is a titled list of the lines describing one part of the report.
+type section struct {
+	title string
+	lines []string
+}
+
+// Print prints the given domain.Report as ASCII: a section for each of its pages, orphans, findings, duplicates,
+// changes, certificates, errors and thresholds which is not empty, with a line for each item indented under the title.
+// The metadata of a page is indented under its line.
 func (c Printer) Print() (string, error) {
-	return fmt.Sprint(c.content), nil
+	var b strings.Builder
+
+	for _, s := range c.sections() {
+		if len(s.lines) == 0 {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%v (%v)\n", s.title, len(s.lines))
+
+		for _, line := range s.lines {
+			fmt.Fprintf(&b, "  %v\n", line)
+		}
+	}
+
+	if b.Len() == 0 {
+		return "No pages\n", nil
+	}
+
+	return b.String(), nil
 }
 
 // Persist creates a TXT file with the given data.
 func (c Printer) Persist(data string) error {
-	return os.WriteFile("output.txt", []byte(data), 0600)
+	return os.WriteFile(c.output, []byte(data), 0600)
+}
+
+func (c Printer) sections() []section {
+	pages := section{title: "Pages"}
+	for i := range c.content.Pages {
+		pages.lines = append(pages.lines, pageLine(c.content.Pages[i]))
+	}
+
+	orphans := section{title: "Orphans"}
+	for _, u := range c.content.Orphans {
+		orphans.lines = append(orphans.lines, u.String())
+	}
+
+	duplicates := section{title: "Duplicates"}
+	for _, d := range c.content.Duplicates {
+		duplicates.lines = append(duplicates.lines, duplicatesLine(d))
+	}
+
+	certificates := section{title: "Certificates"}
+	for i := range c.content.Certificates {
+		certificates.lines = append(certificates.lines, certificateLine(c.content.Certificates[i]))
+	}
+
+	errs := section{title: "Errors"}
+	for i := range c.content.Errors {
+		e := c.content.Errors[i]
+		errs.lines = append(errs.lines, e.Error()+linkedFrom(e.Referrer))
+	}
+
+	findings := section{title: "Findings"}
+	for _, f := range c.content.Findings {
+		findings.lines = append(findings.lines, fmt.Sprintf("%v %v %v: %v", f.Severity, f.Rule, f.URL.String(), f.Message))
+	}
+
+	thresholds := section{title: "Thresholds"}
+	for _, t := range c.content.Thresholds {
+		thresholds.lines = append(thresholds.lines, t.String())
+	}
+
+	return []section{pages, orphans, findings, duplicates, changes(c.content.Changes), certificates, errs, thresholds}
+}
+
+// changes lists the URLs added, changed, unchanged and removed since the previous crawl, if it was compared with one.
+func changes(ch *domain.Changes) section {
+	s := section{title: "Changes"}
+	if ch == nil {
+		return s
+	}
+
+	for _, kind := range []struct {
+		name string
+		urls []url.URL
+	}{{"added", ch.Added}, {"changed", ch.Changed}, {"unchanged", ch.Unchanged}, {"removed", ch.Removed}} {
+		for _, u := range kind.urls {
+			s.lines = append(s.lines, fmt.Sprintf("%v %v", kind.name, u.String()))
+		}
+	}
+
+	return s
+}
+
+func duplicatesLine(d domain.Duplicates) string {
+	urls := make([]string, len(d.URLs))
+	for i, u := range d.URLs {
+		urls[i] = u.String()
+	}
+
+	kind := "near"
+	if d.Exact {
+		kind = "exact"
+	}
+
+	return fmt.Sprintf("%v %v", kind, strings.Join(urls, ", "))
+}
+
+func certificateLine(cert domain.Certificate) string {
+	expiry := fmt.Sprintf("expires %v, %v days remaining", cert.NotAfter.Format(dateFormat), cert.DaysRemaining)
+	if cert.DaysRemaining < 0 {
+		expiry = fmt.Sprintf("expired %v, %v days ago", cert.NotAfter.Format(dateFormat), -cert.DaysRemaining)
+	}
+
+	return fmt.Sprintf("%v %v issued by %v, %v, %v", cert.Host, cert.Subject, cert.Issuer, cert.TLSVersion, expiry)
+}
+
+// pageLine describes the status of a page, where it was redirected to and where it was linked from.
+func pageLine(page domain.Page) string {
+	line := fmt.Sprintf("%v %v", status(page), page.URL.String())
+
+	if len(page.Redirects) > 0 {
+		line += fmt.Sprintf(" redirected to %v", page.Redirects[len(page.Redirects)-1].String())
+	}
+
+	return line + linkedFrom(page.Referrer) + metadata(page.Metadata)
+}
+
+// metadata describes what a page says about itself, a line for each part it has.
+func metadata(m domain.Metadata) string {
+	var lines []string
+
+	add := func(name, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf("\n    %v: %v", name, value))
+		}
+	}
+
+	add("title", m.Title)
+	add("description", m.Description)
+
+	if m.Canonical != (url.URL{}) {
+		add("canonical", m.Canonical.String())
+	}
+
+	for _, a := range m.Alternates {
+		add("alternate "+a.Lang, a.URL.String())
+	}
+
+	add("h1", strings.Join(m.H1, ", "))
+	add("h2", strings.Join(m.H2, ", "))
+	add("lang", m.Lang)
+
+	if m.WordCount > 0 {
+		add("words", fmt.Sprint(m.WordCount))
+	}
+
+	return strings.Join(lines, "")
+}
+
+func status(page domain.Page) string {
+	switch {
+	case page.Pending:
+		return "not fetched"
+	case page.Unchanged:
+		return fmt.Sprintf("%v unchanged", page.StatusCode)
+	case page.StatusCode == 0:
+		return "no response"
+	default:
+		return fmt.Sprint(page.StatusCode)
+	}
+}
+
+func linkedFrom(referrer url.URL) string {
+	if referrer == (url.URL{}) {
+		return ""
+	}
+
+	return fmt.Sprintf(" linked from %v", referrer.String())
 }
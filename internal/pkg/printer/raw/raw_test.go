@@ -2,7 +2,9 @@ package raw
 
 import (
 	"crawler/internal/domain"
+	"errors"
 	"net/url"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,30 +14,154 @@ import (
 func TestContentTypeRaw_Print_Success(t *testing.T) {
 	tests := []struct {
 		name         string
-		givenContent []domain.Page
+		givenContent domain.Report
 		expected     string
 	}{
 		{
-			name: "given pages, expect raw output",
-			givenContent: []domain.Page{
-				{
-					URL: url.URL{
-						Host: "example.com",
-						Path: "/test/",
+			name: "given a report, expect a section for each part of it which is not empty",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL:        url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+						StatusCode: 200,
+						CrawledAt:  time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 					},
-					Referrer: url.URL{
-						Host: "example.com",
+					{
+						URL:        url.URL{Scheme: "https", Host: "example.com", Path: "/moved/"},
+						Referrer:   url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+						StatusCode: 200,
+						Redirects:  []url.URL{{Scheme: "https", Host: "example.com", Path: "/new/"}},
+					},
+					{
+						URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/missing/"},
+						Referrer: url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+					},
+					{
+						URL:     url.URL{Scheme: "https", Host: "example.com", Path: "/queued/"},
+						Pending: true,
+					},
+				},
+				Errors: []domain.CrawlError{
+					{
+						URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/missing/"},
+						Referrer: url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+						Phase:    domain.PhaseFetch,
+						Err:      errors.New("connection refused"),
 					},
-					CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 				},
+				Findings: []domain.Finding{
+					{
+						Rule:     "missing-title",
+						Severity: domain.SeverityError,
+						URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+						Message:  "page has no title",
+					},
+				},
+				Thresholds: []domain.Threshold{{Name: "broken-links", Limit: 0, Actual: 1}},
 			},
-			expected: "[{{   example.com /test/  false   } {   example.com   false   } 2021-06-10 16:00:00 +0000 UTC}]",
+			expected: `Pages (4)
+  200 https://example.com/
+  200 https://example.com/moved/ redirected to https://example.com/new/ linked from https://example.com/
+  no response https://example.com/missing/ linked from https://example.com/
+  not fetched https://example.com/queued/
+
+Findings (1)
+  error missing-title https://example.com/: page has no title
+
+Errors (1)
+  fetch https://example.com/missing/: connection refused linked from https://example.com/
+
+Thresholds (1)
+  broken-links is 1, more than the 0 allowed
+`,
+		},
+		{
+			name: "given a page with metadata, duplicates, changes and certificates, expect each printed",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL:        url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+						StatusCode: 200,
+						Metadata: domain.Metadata{
+							Title:       "Home",
+							Description: "The home page",
+							Canonical:   url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+							Alternates:  []domain.Alternate{{Lang: "de", URL: url.URL{Scheme: "https", Host: "example.de", Path: "/"}}},
+							H1:          []string{"Welcome"},
+							H2:          []string{"News", "Contact"},
+							Lang:        "en",
+							WordCount:   120,
+						},
+					},
+				},
+				Duplicates: []domain.Duplicates{
+					{
+						Exact: true,
+						URLs:  []url.URL{{Scheme: "https", Host: "example.com", Path: "/a/"}, {Scheme: "https", Host: "example.com", Path: "/b/"}},
+					},
+					{URLs: []url.URL{{Scheme: "https", Host: "example.com", Path: "/c/"}, {Scheme: "https", Host: "example.com", Path: "/d/"}}},
+				},
+				Changes: &domain.Changes{
+					Added:     []url.URL{{Scheme: "https", Host: "example.com", Path: "/new/"}},
+					Changed:   []url.URL{{Scheme: "https", Host: "example.com", Path: "/"}},
+					Unchanged: []url.URL{{Scheme: "https", Host: "example.com", Path: "/about/"}},
+					Removed:   []url.URL{{Scheme: "https", Host: "example.com", Path: "/old/"}},
+				},
+				Certificates: []domain.Certificate{
+					{
+						Host:          "example.com",
+						Subject:       "CN=example.com",
+						Issuer:        "CN=Example CA",
+						NotAfter:      time.Date(2021, 07, 10, 00, 00, 00, 00, time.UTC),
+						TLSVersion:    "TLS 1.3",
+						DaysRemaining: 30,
+					},
+					{
+						Host:          "old.example.com",
+						Subject:       "CN=old.example.com",
+						Issuer:        "CN=Example CA",
+						NotAfter:      time.Date(2021, 06, 01, 00, 00, 00, 00, time.UTC),
+						TLSVersion:    "TLS 1.2",
+						DaysRemaining: -9,
+					},
+				},
+			},
+			expected: `Pages (1)
+  200 https://example.com/
+    title: Home
+    description: The home page
+    canonical: https://example.com/
+    alternate de: https://example.de/
+    h1: Welcome
+    h2: News, Contact
+    lang: en
+    words: 120
+
+Duplicates (2)
+  exact https://example.com/a/, https://example.com/b/
+  near https://example.com/c/, https://example.com/d/
+
+Changes (4)
+  added https://example.com/new/
+  changed https://example.com/
+  unchanged https://example.com/about/
+  removed https://example.com/old/
+
+Certificates (2)
+  example.com CN=example.com issued by CN=Example CA, TLS 1.3, expires 2021-07-10, 30 days remaining
+  old.example.com CN=old.example.com issued by CN=Example CA, TLS 1.2, expired 2021-06-01, 9 days ago
+`,
+		},
+		{
+			name:         "given an empty report, expect no pages",
+			givenContent: domain.Report{},
+			expected:     "No pages\n",
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := New(test.givenContent).Print()
+			actual, err := New(test.givenContent, "").Print()
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -50,16 +176,18 @@ func TestContentTypeRaw_Print_Success(t *testing.T) {
 func TestContentTypeRaw_Persist_Success(t *testing.T) {
 	tests := []struct {
 		name         string
-		givenContent []domain.Page
+		givenContent domain.Report
 	}{
 		{
-			name: "given pages, expect to be persisted",
-			givenContent: []domain.Page{
-				{
-					Referrer: url.URL{
-						Host: "example.com",
+			name: "given a report, expect to be persisted",
+			givenContent: domain.Report{
+				Pages: []domain.Page{
+					{
+						Referrer: url.URL{
+							Host: "example.com",
+						},
+						CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 					},
-					CrawledAt: time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC),
 				},
 			},
 		},
@@ -67,7 +195,7 @@ func TestContentTypeRaw_Persist_Success(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			printer := New(test.givenContent)
+			printer := New(test.givenContent, filepath.Join(t.TempDir(), "output.txt"))
 
 			content, err := printer.Print()
 			if err != nil {
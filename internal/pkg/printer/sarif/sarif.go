@@ -0,0 +1,200 @@
+package sarif
+
+import (
+	"crawler/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// ErrFailedToMarshal is returned if the marshaller fails.
+var (
+	ErrFailedToMarshal = errors.New("failed to marshal content")
+)
+
+// DefaultOutput is the file written to when no output is given.
+const DefaultOutput = "output.sarif"
+
+const (
+	version = "2.1.0"
+	schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// ruleCrawlError is the rule of the results for URLs which could not be crawled.
+	ruleCrawlError = "crawl-error"
+	// ruleThresholdPrefix prefixes the name of a threshold for the rule of its results, so it cannot collide with the
+	// rule of a finding, such as redirect-chain.
+	ruleThresholdPrefix = "threshold/"
+)
+
+// Levels of a SARIF result.
+const (
+	levelError   = "error"
+	levelWarning = "warning"
+	levelNote    = "note"
+)
+
+// Printer prints and persists in SARIF 2.1.0 for a given domain.Report, so CI systems can annotate the failing URLs.
+type Printer struct {
+	content domain.Report
+	output  string
+}
+
+// New instantiates a SARIF Printer which persists to output, or DefaultOutput if empty.
+func New(content domain.Report, output string) Printer {
+	if output == "" {
+		output = DefaultOutput
+	}
+
+	return Printer{
+		content: content,
+		output:  output,
+	}
+}
+
+type log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []run  `json:"runs"`
+}
+
+type run struct {
+	Tool    tool     `json:"tool"`
+	Results []result `json:"results"`
+}
+
+type tool struct {
+	Driver driver `json:"driver"`
+}
+
+type driver struct {
+	Name  string `json:"name"`
+	Rules []rule `json:"rules"`
+}
+
+type rule struct {
+	ID string `json:"id"`
+}
+
+type result struct {
+	RuleID    string     `json:"ruleId"`
+	Level     string     `json:"level"`
+	Message   message    `json:"message"`
+	Locations []location `json:"locations"`
+}
+
+type message struct {
+	Text string `json:"text"`
+}
+
+type location struct {
+	PhysicalLocation physicalLocation `json:"physicalLocation"`
+}
+
+type physicalLocation struct {
+	ArtifactLocation artifactLocation `json:"artifactLocation"`
+}
+
+type artifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Print shows a result for every URL of an exceeded threshold, every crawl error and every finding, in that order.
+func (c Printer) Print() (string, error) {
+	results := make([]result, 0)
+
+	for _, t := range c.content.Thresholds {
+		if !t.Exceeded() {
+			continue
+		}
+
+		for _, u := range t.URLs {
+			results = append(results, newResult(ruleThresholdPrefix+t.Name, levelError, t.String(), u))
+		}
+	}
+
+	for i := range c.content.Errors {
+		e := c.content.Errors[i]
+
+		text := e.Error()
+		if e.Referrer.String() != "" {
+			text = fmt.Sprintf("%v, linked from %v", text, e.Referrer.String())
+		}
+
+		results = append(results, newResult(ruleCrawlError, levelWarning, text, e.URL))
+	}
+
+	for _, f := range c.content.Findings {
+		results = append(results, newResult(f.Rule, level(f.Severity), f.Message, f.URL))
+	}
+
+	b, err := json.MarshalIndent(log{
+		Version: version,
+		Schema:  schema,
+		Runs: []run{
+			{
+				Tool: tool{
+					Driver: driver{
+						Name:  "crawler",
+						Rules: rules(results),
+					},
+				},
+				Results: results,
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, ErrFailedToMarshal)
+	}
+
+	return string(b), nil
+}
+
+// Persist creates a SARIF file with the given data.
+func (c Printer) Persist(data string) error {
+	return os.WriteFile(c.output, []byte(data), 0600)
+}
+
+func newResult(ruleID, level, text string, u url.URL) result {
+	return result{
+		RuleID:  ruleID,
+		Level:   level,
+		Message: message{Text: text},
+		Locations: []location{
+			{
+				PhysicalLocation: physicalLocation{
+					ArtifactLocation: artifactLocation{URI: u.String()},
+				},
+			},
+		},
+	}
+}
+
+// level maps the severity of a finding to the level of a result.
+func level(severity domain.Severity) string {
+	switch severity {
+	case domain.SeverityError:
+		return levelError
+	case domain.SeverityWarning:
+		return levelWarning
+	default:
+		return levelNote
+	}
+}
+
+// rules lists the rule of every result once, in the order they are first used.
+func rules(results []result) []rule {
+	seen := make(map[string]bool)
+	rr := make([]rule, 0)
+
+	for _, r := range results {
+		if seen[r.RuleID] {
+			continue
+		}
+
+		seen[r.RuleID] = true
+		rr = append(rr, rule{ID: r.RuleID})
+	}
+
+	return rr
+}
@@ -0,0 +1,162 @@
+package sarif
+
+import (
+	"crawler/internal/domain"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrinter_Print_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+		expected     log
+	}{
+		{
+			name: "given exceeded thresholds, crawl errors and findings, expect a result for each",
+			givenContent: domain.Report{
+				Thresholds: []domain.Threshold{
+					{
+						Name:     "broken-links",
+						Limit:    0,
+						Actual:   1,
+						URLs:     []url.URL{{Scheme: "https", Host: "example.com", Path: "/missing/"}},
+						ExitCode: 2,
+					},
+					{
+						Name:     "server-errors",
+						Limit:    0,
+						ExitCode: 3,
+					},
+				},
+				Errors: []domain.CrawlError{
+					{
+						URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/missing/"},
+						Referrer: url.URL{Scheme: "https", Host: "example.com"},
+						Phase:    domain.PhaseFetch,
+						Status:   404,
+						Err:      errors.New("404: invalid status code received"),
+					},
+				},
+				Findings: []domain.Finding{
+					{
+						Rule:     "missing-title",
+						Severity: domain.SeverityInfo,
+						URL:      url.URL{Scheme: "https", Host: "example.com"},
+						Message:  "page has no title",
+					},
+				},
+			},
+			expected: log{
+				Version: version,
+				Schema:  schema,
+				Runs: []run{
+					{
+						Tool: tool{
+							Driver: driver{
+								Name:  "crawler",
+								Rules: []rule{{ID: "threshold/broken-links"}, {ID: "crawl-error"}, {ID: "missing-title"}},
+							},
+						},
+						Results: []result{
+							{
+								RuleID:    "threshold/broken-links",
+								Level:     "error",
+								Message:   message{Text: "broken-links is 1, more than the 0 allowed"},
+								Locations: locations("https://example.com/missing/"),
+							},
+							{
+								RuleID: "crawl-error",
+								Level:  "warning",
+								Message: message{
+									Text: "fetch https://example.com/missing/: 404: invalid status code received, linked from https://example.com",
+								},
+								Locations: locations("https://example.com/missing/"),
+							},
+							{
+								RuleID:    "missing-title",
+								Level:     "note",
+								Message:   message{Text: "page has no title"},
+								Locations: locations("https://example.com"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:         "given an empty report, expect no results",
+			givenContent: domain.Report{},
+			expected: log{
+				Version: version,
+				Schema:  schema,
+				Runs: []run{
+					{
+						Tool:    tool{Driver: driver{Name: "crawler", Rules: []rule{}}},
+						Results: []result{},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(test.givenContent, "").Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var a log
+
+			err = json.Unmarshal([]byte(actual), &a)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(a, test.expected) {
+				t.Fatal(cmp.Diff(a, test.expected))
+			}
+		})
+	}
+}
+
+func TestPrinter_Persist_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenContent domain.Report
+	}{
+		{
+			name: "given a report, expect to be persisted",
+			givenContent: domain.Report{
+				Findings: []domain.Finding{{Rule: "missing-title"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			printer := New(test.givenContent, filepath.Join(t.TempDir(), "output.sarif"))
+
+			content, err := printer.Print()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = printer.Persist(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// locations is where a result for the URL is.
+func locations(uri string) []location {
+	return []location{{PhysicalLocation: physicalLocation{ArtifactLocation: artifactLocation{URI: uri}}}}
+}
@@ -0,0 +1,188 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRefresh is how often the progress line of a terminal is redrawn.
+	DefaultRefresh = 200 * time.Millisecond
+	// DefaultInterval is how often a summary line is written when the output is not a terminal.
+	DefaultInterval = 10 * time.Second
+)
+
+// clearLine returns the cursor to the start of the line and erases it, so the progress line is redrawn in place.
+const clearLine = "\r\033[K"
+
+// Progress counts the pages of a crawl and reports them to out while it runs. On a terminal a single line is redrawn
+// in place, otherwise a summary line is written every interval.
+type Progress struct {
+	out      io.Writer
+	terminal bool
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	started  time.Time
+	queued   int
+	inFlight int
+	done     int
+	errors   int
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// New instantiates a Progress which reports to out, redrawing a single line if out is a terminal or writing a summary
+// every interval otherwise.
+func New(out io.Writer, terminal bool, interval time.Duration) *Progress {
+	if terminal {
+		interval = DefaultRefresh
+	}
+
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &Progress{
+		out:      out,
+		terminal: terminal,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Snapshot is the progress of a crawl at a point in time.
+type Snapshot struct {
+	Done     int
+	Queued   int
+	InFlight int
+	Errors   int
+	Elapsed  time.Duration
+}
+
+// Rate is the number of pages done each second.
+func (s Snapshot) Rate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(s.Done) / s.Elapsed.Seconds()
+}
+
+// String summarises the Snapshot in a single line.
+func (s Snapshot) String() string {
+	return fmt.Sprintf("%v pages done, %v queued, %v in flight, %v errors, %.1f pages/s, %v elapsed",
+		s.Done, s.Queued, s.InFlight, s.Errors, s.Rate(), s.Elapsed.Truncate(time.Second))
+}
+
+// Start begins reporting progress until Stop is called.
+func (p *Progress) Start() {
+	p.mu.Lock()
+	p.started = p.now()
+	p.mu.Unlock()
+
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+
+	go p.report()
+}
+
+// Stop ends reporting and writes the final progress of the crawl.
+func (p *Progress) Stop() {
+	if p.stop == nil {
+		return
+	}
+
+	close(p.stop)
+	<-p.stopped
+
+	if p.terminal {
+		_, _ = fmt.Fprintf(p.out, "%v%v\n", clearLine, p.Snapshot())
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(p.out, p.Snapshot())
+}
+
+func (p *Progress) report() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.write()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *Progress) write() {
+	if p.terminal {
+		_, _ = fmt.Fprintf(p.out, "%v%v", clearLine, p.Snapshot())
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(p.out, p.Snapshot())
+}
+
+// Snapshot returns the progress of the crawl so far.
+func (p *Progress) Snapshot() Snapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var elapsed time.Duration
+	if !p.started.IsZero() {
+		elapsed = p.now().Sub(p.started)
+	}
+
+	return Snapshot{
+		Done:     p.done,
+		Queued:   p.queued,
+		InFlight: p.inFlight,
+		Errors:   p.errors,
+		Elapsed:  elapsed,
+	}
+}
+
+// PageQueued counts a page waiting to be fetched.
+func (p *Progress) PageQueued() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queued++
+}
+
+// FetchStarted counts a queued page being fetched.
+func (p *Progress) FetchStarted() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queued--
+	p.inFlight++
+}
+
+// FetchFinished counts a page as done, and as an error if errorClass is not empty.
+func (p *Progress) FetchFinished(errorClass string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight--
+	p.done++
+
+	if errorClass != "" {
+		p.errors++
+	}
+}
+
+// BodyRead is a no-op, as the bytes read are not part of the progress.
+func (p *Progress) BodyRead(_ int) {}
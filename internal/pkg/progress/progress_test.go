@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProgress_Stop(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenTerminal bool
+		givenElapsed  time.Duration
+		givenCounted  func(p *Progress)
+		expected      string
+	}{
+		{
+			name:         "given a crawl in progress which is not on a terminal, expect a summary line",
+			givenElapsed: 4 * time.Second,
+			givenCounted: func(p *Progress) {
+				for i := 0; i < 12; i++ {
+					p.PageQueued()
+				}
+
+				for i := 0; i < 10; i++ {
+					p.FetchStarted()
+				}
+
+				for i := 0; i < 8; i++ {
+					p.FetchFinished("")
+				}
+
+				p.FetchFinished("timeout")
+			},
+			expected: "9 pages done, 2 queued, 1 in flight, 1 errors, 2.2 pages/s, 4s elapsed\n",
+		},
+		{
+			name:          "given a crawl on a terminal, expect the progress line to be redrawn in place",
+			givenTerminal: true,
+			givenElapsed:  1500 * time.Millisecond,
+			givenCounted: func(p *Progress) {
+				p.PageQueued()
+				p.FetchStarted()
+				p.FetchFinished("")
+			},
+			expected: clearLine + "1 pages done, 0 queued, 0 in flight, 0 errors, 0.7 pages/s, 1s elapsed\n",
+		},
+		{
+			name:         "given nothing has been crawled, expect no rate",
+			givenCounted: func(p *Progress) {},
+			expected:     "0 pages done, 0 queued, 0 in flight, 0 errors, 0.0 pages/s, 0s elapsed\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			p := New(&out, test.givenTerminal, time.Hour)
+			p.interval = time.Hour
+
+			started := time.Date(2021, 06, 10, 16, 00, 00, 00, time.UTC)
+			p.now = func() time.Time { return started }
+
+			p.Start()
+			test.givenCounted(p)
+			p.now = func() time.Time { return started.Add(test.givenElapsed) }
+			p.Stop()
+
+			if !cmp.Equal(out.String(), test.expected) {
+				t.Fatal(cmp.Diff(out.String(), test.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"crawler/internal/domain"
+	"math"
+	"sort"
+)
+
+// certificates finds the TLS certificate of each host, as first seen in the crawl, along with how many days it was
+// still valid for.
+func certificates(pages []domain.Page) []domain.Certificate {
+	byHost := make(map[string]domain.Certificate)
+
+	for _, page := range pages {
+		if page.Certificate == nil {
+			continue
+		}
+
+		if _, ok := byHost[page.Certificate.Host]; ok {
+			continue
+		}
+
+		c := *page.Certificate
+		c.DaysRemaining = int(math.Floor(c.NotAfter.Sub(page.CrawledAt).Hours() / 24))
+
+		byHost[c.Host] = c
+	}
+
+	var found []domain.Certificate
+	for _, c := range byHost {
+		found = append(found, c)
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Host < found[j].Host
+	})
+
+	return found
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"crawler/internal/domain"
+	"net/url"
+)
+
+// Compare finds the pages of the current crawl which were added, changed or unchanged since the previous crawl, and
+// those of the previous crawl which were not found again. A page has changed if its status code or main text differs.
+// Pending pages were not fetched, so they are left out. If the current crawl is not complete, the pages it did not
+// reach are not reported as removed.
+func Compare(previous, current []domain.Page, complete bool) *domain.Changes {
+	before := make(map[url.URL]domain.Page)
+	for _, page := range previous {
+		before[page.URL] = page
+	}
+
+	found := make(map[url.URL]bool)
+	changes := &domain.Changes{}
+
+	for _, page := range current {
+		found[page.URL] = true
+
+		if page.Pending {
+			continue
+		}
+
+		old, ok := before[page.URL]
+
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, page.URL)
+		case page.Unchanged || (page.StatusCode == old.StatusCode && page.ContentHash == old.ContentHash):
+			changes.Unchanged = append(changes.Unchanged, page.URL)
+		default:
+			changes.Changed = append(changes.Changed, page.URL)
+		}
+	}
+
+	for _, page := range previous {
+		if complete && !found[page.URL] {
+			changes.Removed = append(changes.Removed, page.URL)
+		}
+	}
+
+	sortURLs(changes.Added)
+	sortURLs(changes.Changed)
+	sortURLs(changes.Unchanged)
+	sortURLs(changes.Removed)
+
+	return changes
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"crawler/internal/domain"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenPrevious   []domain.Page
+		givenCurrent    []domain.Page
+		givenIncomplete bool
+		expectedChanges *domain.Changes
+	}{
+		{
+			name: "given a previous and current crawl, expect pages added, changed, unchanged and removed",
+			givenPrevious: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/same/"}, StatusCode: http.StatusOK, ContentHash: "a"},
+				{URL: url.URL{Host: "example.com", Path: "/not-modified/"}, StatusCode: http.StatusOK, ContentHash: "b"},
+				{URL: url.URL{Host: "example.com", Path: "/edited/"}, StatusCode: http.StatusOK, ContentHash: "c"},
+				{URL: url.URL{Host: "example.com", Path: "/broken/"}, StatusCode: http.StatusOK, ContentHash: "d"},
+				{URL: url.URL{Host: "example.com", Path: "/gone/"}, StatusCode: http.StatusOK},
+			},
+			givenCurrent: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/same/"}, StatusCode: http.StatusOK, ContentHash: "a"},
+				{URL: url.URL{Host: "example.com", Path: "/not-modified/"}, StatusCode: http.StatusOK, Unchanged: true},
+				{URL: url.URL{Host: "example.com", Path: "/edited/"}, StatusCode: http.StatusOK, ContentHash: "e"},
+				{URL: url.URL{Host: "example.com", Path: "/broken/"}, StatusCode: http.StatusNotFound},
+				{URL: url.URL{Host: "example.com", Path: "/new/"}, StatusCode: http.StatusOK},
+			},
+			expectedChanges: &domain.Changes{
+				Added: []url.URL{
+					{Host: "example.com", Path: "/new/"},
+				},
+				Changed: []url.URL{
+					{Host: "example.com", Path: "/broken/"},
+					{Host: "example.com", Path: "/edited/"},
+				},
+				Unchanged: []url.URL{
+					{Host: "example.com", Path: "/not-modified/"},
+					{Host: "example.com", Path: "/same/"},
+				},
+				Removed: []url.URL{
+					{Host: "example.com", Path: "/gone/"},
+				},
+			},
+		},
+		{
+			name:            "given no previous crawl, expect every page added",
+			givenCurrent:    []domain.Page{{URL: url.URL{Host: "example.com"}}},
+			expectedChanges: &domain.Changes{Added: []url.URL{{Host: "example.com"}}},
+		},
+		{
+			name: "given a crawl which was stopped early, expect pending pages and those not reached to be left out",
+			givenPrevious: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/same/"}, StatusCode: http.StatusOK, ContentHash: "a"},
+				{URL: url.URL{Host: "example.com", Path: "/queued/"}, StatusCode: http.StatusOK, ContentHash: "b"},
+				{URL: url.URL{Host: "example.com", Path: "/not-reached/"}, StatusCode: http.StatusOK},
+			},
+			givenCurrent: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/same/"}, StatusCode: http.StatusOK, ContentHash: "a"},
+				{URL: url.URL{Host: "example.com", Path: "/queued/"}, Pending: true},
+			},
+			givenIncomplete: true,
+			expectedChanges: &domain.Changes{
+				Unchanged: []url.URL{{Host: "example.com", Path: "/same/"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Compare(test.givenPrevious, test.givenCurrent, !test.givenIncomplete)
+
+			if !cmp.Equal(actual, test.expectedChanges) {
+				t.Fatal(cmp.Diff(actual, test.expectedChanges))
+			}
+		})
+	}
+}
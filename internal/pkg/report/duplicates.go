@@ -0,0 +1,118 @@
+package report
+
+import (
+	"crawler/internal/domain"
+	"crawler/internal/pkg/fingerprint"
+	"net/url"
+	"sort"
+)
+
+// NearDuplicateDistance is the most bits two SimHashes can differ by for their pages to be near duplicates.
+const NearDuplicateDistance = 6
+
+// duplicates groups the pages whose main text is identical, then groups those whose main text is nearly identical.
+// Each group of exact duplicates is compared as one, so it is only part of a near duplicate group if its text is
+// close to that of other pages.
+func duplicates(pages []domain.Page) []domain.Duplicates {
+	byHash := make(map[string][]url.URL)
+	simHashes := make(map[string]uint64)
+
+	var hashes []string
+
+	for _, page := range pages {
+		if page.ContentHash == "" {
+			continue
+		}
+
+		if _, ok := byHash[page.ContentHash]; !ok {
+			hashes = append(hashes, page.ContentHash)
+			simHashes[page.ContentHash] = page.SimHash
+		}
+
+		byHash[page.ContentHash] = append(byHash[page.ContentHash], page.URL)
+	}
+
+	var found []domain.Duplicates
+
+	for _, hash := range hashes {
+		if len(byHash[hash]) > 1 {
+			found = append(found, domain.Duplicates{Exact: true, URLs: sortURLs(byHash[hash])})
+		}
+	}
+
+	for _, group := range nearGroups(hashes, simHashes) {
+		var urls []url.URL
+
+		for _, hash := range group {
+			urls = append(urls, byHash[hash]...)
+		}
+
+		found = append(found, domain.Duplicates{URLs: sortURLs(urls)})
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Exact != found[j].Exact {
+			return found[i].Exact
+		}
+
+		return found[i].URLs[0].String() < found[j].URLs[0].String()
+	})
+
+	return found
+}
+
+// nearGroups joins every pair of hashes whose SimHashes are within NearDuplicateDistance, returning each group of
+// more than one hash. Every pair is compared, so this grows with the square of the number of distinct pages.
+func nearGroups(hashes []string, simHashes map[string]uint64) [][]string {
+	parent := make([]int, len(hashes))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	root := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+
+		return i
+	}
+
+	for i := range hashes {
+		for j := i + 1; j < len(hashes); j++ {
+			if fingerprint.Distance(simHashes[hashes[i]], simHashes[hashes[j]]) <= NearDuplicateDistance {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	members := make(map[int][]string)
+	var roots []int
+
+	for i, hash := range hashes {
+		r := root(i)
+		if _, ok := members[r]; !ok {
+			roots = append(roots, r)
+		}
+
+		members[r] = append(members[r], hash)
+	}
+
+	var groups [][]string
+
+	for _, r := range roots {
+		if len(members[r]) > 1 {
+			groups = append(groups, members[r])
+		}
+	}
+
+	return groups
+}
+
+func sortURLs(urls []url.URL) []url.URL {
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].String() < urls[j].String()
+	})
+
+	return urls
+}
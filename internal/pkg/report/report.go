@@ -0,0 +1,29 @@
+package report
+
+import (
+	"crawler/internal/domain"
+	"net/url"
+)
+
+// New builds a domain.Report from the pages of a crawl.
+func New(pages []domain.Page) domain.Report {
+	return domain.Report{
+		Pages:        pages,
+		Orphans:      orphans(pages),
+		Duplicates:   duplicates(pages),
+		Certificates: certificates(pages),
+	}
+}
+
+// orphans finds the pages listed in a sitemap which no crawled page links to.
+func orphans(pages []domain.Page) []url.URL {
+	var found []url.URL
+
+	for _, page := range pages {
+		if page.Source == domain.SourceSitemap && !page.Linked {
+			found = append(found, page.URL)
+		}
+	}
+
+	return found
+}
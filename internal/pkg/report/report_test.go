@@ -0,0 +1,146 @@
+package report
+
+import (
+	"crawler/internal/domain"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenPages     []domain.Page
+		expectedReport domain.Report
+	}{
+		{
+			name: "given a sitemap page that is never linked, expect it reported as an orphan",
+			givenPages: []domain.Page{
+				{
+					URL:    url.URL{Host: "example.com", Path: "/"},
+					Source: domain.SourceSeed,
+				},
+				{
+					URL:    url.URL{Host: "example.com", Path: "/linked/"},
+					Source: domain.SourceSitemap,
+					Linked: true,
+				},
+				{
+					URL:    url.URL{Host: "example.com", Path: "/orphan/"},
+					Source: domain.SourceSitemap,
+				},
+			},
+			expectedReport: domain.Report{
+				Pages: []domain.Page{
+					{
+						URL:    url.URL{Host: "example.com", Path: "/"},
+						Source: domain.SourceSeed,
+					},
+					{
+						URL:    url.URL{Host: "example.com", Path: "/linked/"},
+						Source: domain.SourceSitemap,
+						Linked: true,
+					},
+					{
+						URL:    url.URL{Host: "example.com", Path: "/orphan/"},
+						Source: domain.SourceSitemap,
+					},
+				},
+				Orphans: []url.URL{
+					{Host: "example.com", Path: "/orphan/"},
+				},
+			},
+		},
+		{
+			name: "given pages with the same and similar text, expect exact and near duplicate groups",
+			givenPages: []domain.Page{
+				{URL: url.URL{Host: "example.com", Path: "/b/"}, ContentHash: "same", SimHash: 0xff},
+				{URL: url.URL{Host: "example.com", Path: "/a/"}, ContentHash: "same", SimHash: 0xff},
+				{URL: url.URL{Host: "example.com", Path: "/c/"}, ContentHash: "similar", SimHash: 0xfe},
+				{URL: url.URL{Host: "example.com", Path: "/d/"}, ContentHash: "different", SimHash: 0xffff0000},
+				{URL: url.URL{Host: "example.com", Path: "/e.pdf"}},
+			},
+			expectedReport: domain.Report{
+				Pages: []domain.Page{
+					{URL: url.URL{Host: "example.com", Path: "/b/"}, ContentHash: "same", SimHash: 0xff},
+					{URL: url.URL{Host: "example.com", Path: "/a/"}, ContentHash: "same", SimHash: 0xff},
+					{URL: url.URL{Host: "example.com", Path: "/c/"}, ContentHash: "similar", SimHash: 0xfe},
+					{URL: url.URL{Host: "example.com", Path: "/d/"}, ContentHash: "different", SimHash: 0xffff0000},
+					{URL: url.URL{Host: "example.com", Path: "/e.pdf"}},
+				},
+				Duplicates: []domain.Duplicates{
+					{
+						Exact: true,
+						URLs: []url.URL{
+							{Host: "example.com", Path: "/a/"},
+							{Host: "example.com", Path: "/b/"},
+						},
+					},
+					{
+						URLs: []url.URL{
+							{Host: "example.com", Path: "/a/"},
+							{Host: "example.com", Path: "/b/"},
+							{Host: "example.com", Path: "/c/"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New(test.givenPages)
+
+			if !cmp.Equal(actual, test.expectedReport) {
+				t.Fatal(cmp.Diff(actual, test.expectedReport))
+			}
+		})
+	}
+}
+
+func TestNew_Certificates(t *testing.T) {
+	tests := []struct {
+		name                 string
+		givenPages           []domain.Page
+		expectedCertificates []domain.Certificate
+	}{
+		{
+			name: "given pages fetched over TLS, expect one certificate per host with the days it had left",
+			givenPages: []domain.Page{
+				{
+					URL:         url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+					CrawledAt:   time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC),
+					Certificate: &domain.Certificate{Host: "example.com", NotAfter: time.Date(2021, 6, 20, 0, 0, 0, 0, time.UTC)},
+				},
+				{
+					URL:         url.URL{Scheme: "https", Host: "example.com", Path: "/a/"},
+					CrawledAt:   time.Date(2021, 6, 11, 12, 0, 0, 0, time.UTC),
+					Certificate: &domain.Certificate{Host: "example.com", NotAfter: time.Date(2021, 6, 20, 0, 0, 0, 0, time.UTC)},
+				},
+				{
+					URL:         url.URL{Scheme: "https", Host: "expired.com", Path: "/"},
+					CrawledAt:   time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC),
+					Certificate: &domain.Certificate{Host: "expired.com", NotAfter: time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC)},
+				},
+				{
+					URL: url.URL{Scheme: "http", Host: "plain.com", Path: "/"},
+				},
+			},
+			expectedCertificates: []domain.Certificate{
+				{Host: "example.com", NotAfter: time.Date(2021, 6, 20, 0, 0, 0, 0, time.UTC), DaysRemaining: 9},
+				{Host: "expired.com", NotAfter: time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC), DaysRemaining: -1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New(test.givenPages).Certificates
+
+			if !cmp.Equal(actual, test.expectedCertificates) {
+				t.Fatal(cmp.Diff(actual, test.expectedCertificates))
+			}
+		})
+	}
+}
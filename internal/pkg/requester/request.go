@@ -8,11 +8,15 @@ import (
 	"net/url"
 )
 
+// DefaultUserAgent identifies the crawler when no User-Agent is configured.
+const DefaultUserAgent = "web-crawler"
+
 // Requester builds a http.Request for the given inputs.
 type Requester struct {
 	url    url.URL
 	body   io.Reader
 	method string
+	header http.Header
 }
 
 // New instantiates a Requester.
@@ -41,7 +45,41 @@ func (r *Requester) WithMethod(method string) httpclient.Requester {
 	return r
 }
 
-// Build takes the given inputs and creates http.Request with the given context.Context.
+// WithHeader sets a header of the http.Request, replacing any value it already has.
+func (r *Requester) WithHeader(key, value string) httpclient.Requester {
+	if r.header == nil {
+		r.header = make(http.Header)
+	}
+
+	r.header.Set(key, value)
+
+	return r
+}
+
+// WithHeaders sets every given header of the http.Request, replacing any values they already have.
+func (r *Requester) WithHeaders(header http.Header) httpclient.Requester {
+	for key, values := range header {
+		if r.header == nil {
+			r.header = make(http.Header)
+		}
+
+		r.header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+
+	return r
+}
+
+// Build takes the given inputs and creates http.Request with the given context.Context. Headers are kept between
+// builds, so a Requester can be given the headers of every request once.
 func (r *Requester) Build(ctx context.Context) (*http.Request, error) {
-	return http.NewRequestWithContext(ctx, r.method, r.url.String(), r.body)
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url.String(), r.body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range r.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	return req, nil
 }
@@ -19,6 +19,8 @@ func TestRequester_Build(t *testing.T) {
 		givenURL        url.URL
 		givenBody       io.Reader
 		givenMethod     string
+		givenHeaders    http.Header
+		givenUserAgent  string
 		expectedRequest *http.Request
 	}{
 		{
@@ -44,10 +46,43 @@ func TestRequester_Build(t *testing.T) {
 				Host:          "example.com",
 			},
 		},
+		{
+			name: "given headers and a User-Agent, expect them to be set on the request",
+			givenURL: url.URL{
+				Scheme: "https",
+				Host:   "example.com",
+			},
+			givenMethod: http.MethodGet,
+			givenHeaders: http.Header{
+				"x-staging-token": {"secret"},
+				"User-Agent":      {"replaced"},
+			},
+			givenUserAgent: "crawler-test",
+			expectedRequest: &http.Request{
+				Method: http.MethodGet,
+				URL: &url.URL{
+					Scheme: "https",
+					Host:   "example.com",
+				},
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header: http.Header{
+					"X-Staging-Token": {"secret"},
+					"User-Agent":      {"crawler-test"},
+				},
+				Host: "example.com",
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := New().
+			r := New().WithHeaders(test.givenHeaders)
+			if test.givenUserAgent != "" {
+				r = r.WithHeader("User-Agent", test.givenUserAgent)
+			}
+
+			actual, err := r.
 				WithURL(test.givenURL).
 				WithMethod(test.givenMethod).
 				WithBody(test.givenBody).
@@ -0,0 +1,75 @@
+package scope
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// ErrInvalidPattern is returned if a scope rule is not a valid regular expression.
+var (
+	ErrInvalidPattern = errors.New("invalid scope pattern")
+)
+
+// Scope decides whether a url.URL should be crawled based on include and exclude rules.
+type Scope struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// New instantiates a Scope from the given include and exclude regular expressions.
+func New(include, exclude []string) (Scope, error) {
+	in, err := compile(include)
+	if err != nil {
+		return Scope{}, err
+	}
+
+	ex, err := compile(exclude)
+	if err != nil {
+		return Scope{}, err
+	}
+
+	return Scope{
+		include: in,
+		exclude: ex,
+	}, nil
+}
+
+// Allows reports whether the url.URL matches an include rule, if any are set, and none of the exclude rules.
+func (s Scope) Allows(u url.URL) bool {
+	target := u.String()
+
+	for _, re := range s.exclude {
+		if re.MatchString(target) {
+			return false
+		}
+	}
+
+	if len(s.include) == 0 {
+		return true
+	}
+
+	for _, re := range s.include {
+		if re.MatchString(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrInvalidPattern)
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
@@ -0,0 +1,106 @@
+package scope
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestScope_Allows(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenInclude []string
+		givenExclude []string
+		givenURL     url.URL
+		expected     bool
+	}{
+		{
+			name: "given no rules, expect url allowed",
+			givenURL: url.URL{
+				Scheme: "https",
+				Host:   "example.com",
+				Path:   "/blog/",
+			},
+			expected: true,
+		},
+		{
+			name:         "given a matching include rule, expect url allowed",
+			givenInclude: []string{"/blog/"},
+			givenURL: url.URL{
+				Scheme: "https",
+				Host:   "example.com",
+				Path:   "/blog/",
+			},
+			expected: true,
+		},
+		{
+			name:         "given no matching include rule, expect url rejected",
+			givenInclude: []string{"/docs/"},
+			givenURL: url.URL{
+				Scheme: "https",
+				Host:   "example.com",
+				Path:   "/blog/",
+			},
+			expected: false,
+		},
+		{
+			name:         "given a matching exclude rule, expect url rejected even when included",
+			givenInclude: []string{"example.com"},
+			givenExclude: []string{"/blog/"},
+			givenURL: url.URL{
+				Scheme: "https",
+				Host:   "example.com",
+				Path:   "/blog/",
+			},
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := New(test.givenInclude, test.givenExclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := s.Allows(test.givenURL)
+
+			if !cmp.Equal(actual, test.expected) {
+				t.Fatal(cmp.Diff(actual, test.expected))
+			}
+		})
+	}
+}
+
+func TestNew_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenInclude  []string
+		givenExclude  []string
+		expectedError error
+	}{
+		{
+			name:          "given an invalid include pattern, expect error",
+			givenInclude:  []string{"("},
+			expectedError: ErrInvalidPattern,
+		},
+		{
+			name:          "given an invalid exclude pattern, expect error",
+			givenExclude:  []string{"["},
+			expectedError: ErrInvalidPattern,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.givenInclude, test.givenExclude)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package seeds
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Errors returned when reading seeds.
+var (
+	ErrFailedToParseSeed = errors.New("failed to parse seed")
+	ErrFailedToReadSeeds = errors.New("failed to read seeds")
+)
+
+// Parse reads one absolute URL per line, ignoring blank lines and lines starting with #.
+func Parse(r io.Reader) ([]*url.URL, error) {
+	var urls []*url.URL
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		u, err := FromString(line)
+		if err != nil {
+			return nil, err
+		}
+
+		urls = append(urls, u)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToReadSeeds)
+	}
+
+	return urls, nil
+}
+
+// FromStrings parses each of the given absolute URLs.
+func FromStrings(raw []string) ([]*url.URL, error) {
+	var urls []*url.URL
+
+	for _, r := range raw {
+		u, err := FromString(r)
+		if err != nil {
+			return nil, err
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
+
+// FromString parses an absolute URL to be used as a seed.
+func FromString(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseSeed)
+	}
+
+	if !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("%v is not an absolute URL: %w", raw, ErrFailedToParseSeed)
+	}
+
+	return u, nil
+}
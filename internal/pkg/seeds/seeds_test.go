@@ -0,0 +1,78 @@
+package seeds
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParse_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenReader  io.Reader
+		expectedURLs []*url.URL
+	}{
+		{
+			name:        "given one URL per line with comments and blank lines, expect URLs returned",
+			givenReader: strings.NewReader("# docs\nhttps://example.com/docs\n\n  https://example.org  \n"),
+			expectedURLs: []*url.URL{
+				{
+					Scheme: "https",
+					Host:   "example.com",
+					Path:   "/docs",
+				},
+				{
+					Scheme: "https",
+					Host:   "example.org",
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Parse(test.givenReader)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
+
+func TestParse_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenReader   io.Reader
+		expectedError error
+	}{
+		{
+			name:          "given a relative URL, expect error",
+			givenReader:   strings.NewReader("https://example.com\n/docs\n"),
+			expectedError: ErrFailedToParseSeed,
+		},
+		{
+			name:          "given an ASCII control character, expect error",
+			givenReader:   strings.NewReader("https://example.com/" + string(rune(0x7f))),
+			expectedError: ErrFailedToParseSeed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.givenReader)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package sitemap
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// sitemapDirective is the robots.txt field which declares the location of a sitemap.
+const sitemapDirective = "sitemap:"
+
+// ParseRobots returns the absolute URLs of every Sitemap entry within a robots.txt.
+func ParseRobots(r io.Reader) []*url.URL {
+	var urls []*url.URL
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < len(sitemapDirective) || !strings.EqualFold(line[:len(sitemapDirective)], sitemapDirective) {
+			continue
+		}
+
+		u, err := url.Parse(strings.TrimSpace(line[len(sitemapDirective):]))
+		if err != nil || !u.IsAbs() {
+			continue
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls
+}
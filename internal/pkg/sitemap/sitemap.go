@@ -0,0 +1,352 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Errors returned when reading a sitemap.
+var (
+	ErrFailedToParseSitemap = errors.New("failed to parse sitemap")
+	ErrFailedToFetchSitemap = errors.New("failed to fetch sitemap")
+	ErrSitemapTooLarge      = errors.New("sitemap is too large")
+)
+
+// DefaultMaxSize is the most of a sitemap, or a robots.txt, that will be read, unless WithMaxSize is given. It is the
+// largest a sitemap may be, uncompressed, by the sitemap protocol.
+const DefaultMaxSize = 50 << 20
+
+// LoadError is the error of a sitemap which could not be fetched or parsed. It wraps its cause.
+type LoadError struct {
+	URL url.URL
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("%v: %v", e.URL.String(), e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Sitemap holds the page URLs of a urlset and the nested sitemap URLs of a sitemapindex.
+type Sitemap struct {
+	URLs     []*url.URL
+	Sitemaps []*url.URL
+}
+
+type document struct {
+	URLs     []location `xml:"url"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+type location struct {
+	Loc string `xml:"loc"`
+}
+
+// gzipMagic are the first bytes of any gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Parse reads a sitemap urlset or sitemapindex, which may be gzipped, of at most DefaultMaxSize.
+func Parse(r io.Reader) (Sitemap, error) {
+	return parse(r, DefaultMaxSize)
+}
+
+// parse reads a sitemap which may be gzipped. Both the sitemap and, if it is gzipped, its uncompressed content may be
+// at most maxSize, unless it is 0.
+func parse(r io.Reader, maxSize int64) (Sitemap, error) {
+	var doc document
+
+	r, err := decompress(limit(r, maxSize))
+	if err != nil {
+		return Sitemap{}, err
+	}
+
+	r = limit(r, maxSize)
+
+	err = xml.NewDecoder(r).Decode(&doc)
+	if errors.Is(err, ErrSitemapTooLarge) {
+		return Sitemap{}, fmt.Errorf("more than %v bytes: %w", maxSize, ErrSitemapTooLarge)
+	}
+
+	if err != nil {
+		return Sitemap{}, fmt.Errorf("%v: %w", err, ErrFailedToParseSitemap)
+	}
+
+	urls, err := toURLs(doc.URLs)
+	if err != nil {
+		return Sitemap{}, err
+	}
+
+	sitemaps, err := toURLs(doc.Sitemaps)
+	if err != nil {
+		return Sitemap{}, err
+	}
+
+	return Sitemap{
+		URLs:     urls,
+		Sitemaps: sitemaps,
+	}, nil
+}
+
+// ClientProvider gives the ability to perform HTTP Requests.
+type ClientProvider interface {
+	Fetch(ctx context.Context, url url.URL) (*http.Response, error)
+}
+
+// Loader reads sitemaps and follows any nested sitemaps of a sitemapindex.
+type Loader struct {
+	Client  ClientProvider
+	maxSize int64
+}
+
+// Option configures the Loader.
+type Option func(*Loader)
+
+// WithMaxSize sets the most of each sitemap and robots.txt that will be read, and of each gzipped sitemap once it is
+// uncompressed. A sitemap which is larger fails to load. 0 is unlimited.
+func WithMaxSize(maxSize int64) Option {
+	return func(l *Loader) {
+		l.maxSize = maxSize
+	}
+}
+
+// NewLoader instantiates a Loader which reads at most DefaultMaxSize of each sitemap, unless WithMaxSize is given.
+func NewLoader(client ClientProvider, opts ...Option) Loader {
+	l := Loader{
+		Client:  client,
+		maxSize: DefaultMaxSize,
+	}
+
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	return l
+}
+
+// Discover finds the sitemaps of the seed's host, from /sitemap.xml and any Sitemap entries of /robots.txt,
+// and returns every page URL within them. A host without a robots.txt or /sitemap.xml is not an error, nor is a
+// /sitemap.xml which is not XML, such as a page served for a missing one. A sitemap that fails to load does not stop
+// the others from being read, and the first failure is returned as a LoadError.
+func (l Loader) Discover(ctx context.Context, seed url.URL) ([]*url.URL, error) {
+	var urls []*url.URL
+	var errs error
+
+	seen := map[url.URL]bool{}
+
+	for _, location := range l.locations(ctx, seed) {
+		found, err := l.load(ctx, location, seen)
+		if err != nil && errs == nil {
+			errs = err
+		}
+
+		urls = append(urls, found...)
+	}
+
+	defaultLocation := url.URL{Scheme: seed.Scheme, Host: seed.Host, Path: "/sitemap.xml"}
+	if seen[defaultLocation] {
+		return urls, errs
+	}
+
+	seen[defaultLocation] = true
+
+	res, err := l.Client.Fetch(ctx, defaultLocation)
+	if err != nil {
+		return urls, errs
+	}
+	defer res.Body.Close()
+
+	if !isSitemap(res.Header.Get("Content-Type")) {
+		return urls, errs
+	}
+
+	found, err := l.read(ctx, defaultLocation, res.Body, seen)
+	if err != nil && errs == nil {
+		errs = err
+	}
+
+	return append(urls, found...), errs
+}
+
+// locations returns the sitemaps declared in the robots.txt of the seed's host.
+func (l Loader) locations(ctx context.Context, seed url.URL) []url.URL {
+	res, err := l.Client.Fetch(ctx, url.URL{Scheme: seed.Scheme, Host: seed.Host, Path: "/robots.txt"})
+	if err != nil {
+		return nil
+	}
+	defer res.Body.Close()
+
+	var locations []url.URL
+
+	for _, u := range ParseRobots(io.LimitReader(res.Body, l.robotsSize())) {
+		locations = append(locations, *u)
+	}
+
+	return locations
+}
+
+// robotsSize is the most of a robots.txt that will be read. Only the Sitemap entries within it are used, so the rest
+// is ignored rather than failing.
+func (l Loader) robotsSize() int64 {
+	if l.maxSize <= 0 {
+		return math.MaxInt64
+	}
+
+	return l.maxSize
+}
+
+// Load fetches the sitemap at the given url.URL and returns every page URL within it and its nested sitemaps.
+func (l Loader) Load(ctx context.Context, u url.URL) ([]*url.URL, error) {
+	return l.load(ctx, u, map[url.URL]bool{})
+}
+
+// Read parses the given sitemap and returns every page URL within it and its nested sitemaps.
+func (l Loader) Read(ctx context.Context, r io.Reader) ([]*url.URL, error) {
+	s, err := parse(r, l.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.nested(ctx, s, map[url.URL]bool{})
+}
+
+func (l Loader) load(ctx context.Context, u url.URL, seen map[url.URL]bool) ([]*url.URL, error) {
+	if seen[u] {
+		return nil, nil
+	}
+
+	seen[u] = true
+
+	res, err := l.Client.Fetch(ctx, u)
+	if err != nil {
+		return nil, &LoadError{URL: u, Err: fmt.Errorf("%v: %w", err, ErrFailedToFetchSitemap)}
+	}
+	defer res.Body.Close()
+
+	return l.read(ctx, u, res.Body, seen)
+}
+
+// read parses the sitemap fetched from u and loads its nested sitemaps.
+func (l Loader) read(ctx context.Context, u url.URL, r io.Reader, seen map[url.URL]bool) ([]*url.URL, error) {
+	s, err := parse(r, l.maxSize)
+	if err != nil {
+		return nil, &LoadError{URL: u, Err: err}
+	}
+
+	return l.nested(ctx, s, seen)
+}
+
+// nested returns the page URLs of the sitemap along with those of its nested sitemaps.
+func (l Loader) nested(ctx context.Context, s Sitemap, seen map[url.URL]bool) ([]*url.URL, error) {
+	urls := s.URLs
+
+	for _, nested := range s.Sitemaps {
+		found, err := l.load(ctx, *nested, seen)
+		if err != nil {
+			return nil, err
+		}
+
+		urls = append(urls, found...)
+	}
+
+	return urls, nil
+}
+
+// isSitemap reports whether a response of the content type may be a sitemap, which is XML, maybe gzipped, or has no
+// content type at all.
+func isSitemap(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasSuffix(mediaType, "xml") || sitemapTypes[mediaType]
+}
+
+// sitemapTypes are the content types, other than XML, a gzipped sitemap may be served as.
+var sitemapTypes = map[string]bool{
+	"application/gzip":         true,
+	"application/x-gzip":       true,
+	"application/octet-stream": true,
+}
+
+// limit returns a reader of r which fails with ErrSitemapTooLarge once more than maxSize has been read, rather than
+// ending early, so a sitemap which is too large is not mistaken for a broken one. 0 is unlimited.
+func limit(r io.Reader, maxSize int64) io.Reader {
+	if maxSize <= 0 {
+		return r
+	}
+
+	return &limitedReader{r: io.LimitReader(r, maxSize+1), left: maxSize}
+}
+
+// limitedReader is a reader of at most left more bytes.
+type limitedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+
+	if l.left < 0 {
+		return n, ErrSitemapTooLarge
+	}
+
+	return n, err
+}
+
+// decompress returns a reader of the uncompressed content if r is gzipped, otherwise r as it is.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		return br, nil
+	}
+
+	gr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseSitemap)
+	}
+
+	return gr, nil
+}
+
+func toURLs(locations []location) ([]*url.URL, error) {
+	var urls []*url.URL
+
+	for _, l := range locations {
+		loc := strings.TrimSpace(l.Loc)
+		if loc == "" {
+			continue
+		}
+
+		u, err := url.Parse(loc)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseSitemap)
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,382 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParse_Success(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenReader     io.Reader
+		expectedSitemap Sitemap
+	}{
+		{
+			name:        "given a urlset, expect page URLs returned",
+			givenReader: strings.NewReader(urlSet),
+			expectedSitemap: Sitemap{
+				URLs: []*url.URL{
+					{Scheme: "https", Host: "example.com", Path: "/"},
+					{Scheme: "https", Host: "example.com", Path: "/about"},
+				},
+			},
+		},
+		{
+			name:        "given a sitemapindex, expect nested sitemap URLs returned",
+			givenReader: strings.NewReader(sitemapIndex),
+			expectedSitemap: Sitemap{
+				Sitemaps: []*url.URL{
+					{Scheme: "https", Host: "example.com", Path: "/sitemap-pages.xml"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Parse(test.givenReader)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedSitemap) {
+				t.Fatal(cmp.Diff(actual, test.expectedSitemap))
+			}
+		})
+	}
+}
+
+func TestParse_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenReader   io.Reader
+		expectedError error
+	}{
+		{
+			name:          "given invalid XML, expect error",
+			givenReader:   strings.NewReader("<urlset><url>"),
+			expectedError: ErrFailedToParseSitemap,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.givenReader)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestLoader_Read_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenReader  io.Reader
+		givenClient  ClientProvider
+		expectedURLs []*url.URL
+	}{
+		{
+			name:        "given a sitemapindex, expect URLs of the nested sitemaps returned",
+			givenReader: strings.NewReader(sitemapIndex),
+			givenClient: mockClient{
+				GivenBodies: map[string]string{
+					"https://example.com/sitemap-pages.xml": urlSet,
+				},
+			},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/"},
+				{Scheme: "https", Host: "example.com", Path: "/about"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := NewLoader(test.givenClient).Read(context.Background(), test.givenReader)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Path < actual[j].Path
+			})
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
+
+func TestLoader_Read_MaxSize(t *testing.T) {
+	padded := strings.Replace(urlSet, "<urlset", strings.Repeat(" ", 10000)+"<urlset", 1)
+
+	tests := []struct {
+		name          string
+		givenReader   io.Reader
+		givenMaxSize  int64
+		expectedError error
+	}{
+		{
+			name:         "given a sitemap within the maximum size, expect it read",
+			givenReader:  strings.NewReader(urlSet),
+			givenMaxSize: int64(len(urlSet)),
+		},
+		{
+			name:          "given a sitemap larger than the maximum size, expect error",
+			givenReader:   strings.NewReader(urlSet),
+			givenMaxSize:  int64(len(urlSet) / 2),
+			expectedError: ErrSitemapTooLarge,
+		},
+		{
+			name:          "given a gzipped sitemap only larger than the maximum size once uncompressed, expect error",
+			givenReader:   strings.NewReader(gzipped(padded)),
+			givenMaxSize:  int64(len(gzipped(padded)) * 2),
+			expectedError: ErrSitemapTooLarge,
+		},
+		{
+			name:         "given no maximum size, expect any sitemap read",
+			givenReader:  strings.NewReader(gzipped(padded)),
+			givenMaxSize: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewLoader(mockClient{}, WithMaxSize(test.givenMaxSize)).Read(context.Background(), test.givenReader)
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestLoader_Load_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenURL      url.URL
+		givenClient   ClientProvider
+		expectedError error
+	}{
+		{
+			name:          "given the sitemap cannot be fetched, expect error",
+			givenURL:      url.URL{Scheme: "https", Host: "example.com", Path: "/sitemap.xml"},
+			givenClient:   mockClient{},
+			expectedError: ErrFailedToFetchSitemap,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewLoader(test.givenClient).Load(context.Background(), test.givenURL)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+type mockClient struct {
+	GivenBodies       map[string]string
+	GivenContentTypes map[string]string
+}
+
+func (m mockClient) Fetch(_ context.Context, u url.URL) (*http.Response, error) {
+	body, ok := m.GivenBodies[u.String()]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	header := http.Header{}
+	if contentType, ok := m.GivenContentTypes[u.String()]; ok {
+		header.Set("Content-Type", contentType)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+var errNotFound = &url.Error{Op: "Get", Err: io.EOF}
+
+var urlSet = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://example.com/</loc></url>
+	<url><loc> https://example.com/about </loc></url>
+</urlset>`
+
+var sitemapIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>https://example.com/sitemap-pages.xml</loc></sitemap>
+</sitemapindex>`
+
+func TestLoader_Discover_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenSeed    url.URL
+		givenClient  ClientProvider
+		expectedURLs []*url.URL
+	}{
+		{
+			name:      "given a robots.txt declaring a gzipped sitemap index and a /sitemap.xml, expect URLs of both returned",
+			givenSeed: url.URL{Scheme: "https", Host: "example.com", Path: "/blog/"},
+			givenClient: mockClient{
+				GivenBodies: map[string]string{
+					"https://example.com/robots.txt":        "User-agent: *\nDisallow: /admin\nsitemap: https://example.com/index.xml.gz\n",
+					"https://example.com/index.xml.gz":      gzipped(sitemapIndex),
+					"https://example.com/sitemap-pages.xml": urlSet,
+					"https://example.com/sitemap.xml":       `<urlset><url><loc>https://example.com/contact</loc></url></urlset>`,
+				},
+			},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/"},
+				{Scheme: "https", Host: "example.com", Path: "/about"},
+				{Scheme: "https", Host: "example.com", Path: "/contact"},
+			},
+		},
+		{
+			name:      "given a /sitemap.xml which is a HTML page, expect it ignored",
+			givenSeed: url.URL{Scheme: "https", Host: "example.com"},
+			givenClient: mockClient{
+				GivenBodies:       map[string]string{"https://example.com/sitemap.xml": "<html><body>Not found</body></html>"},
+				GivenContentTypes: map[string]string{"https://example.com/sitemap.xml": "text/html; charset=utf-8"},
+			},
+		},
+		{
+			name:      "given a /sitemap.xml served as XML, expect its URLs returned",
+			givenSeed: url.URL{Scheme: "https", Host: "example.com"},
+			givenClient: mockClient{
+				GivenBodies:       map[string]string{"https://example.com/sitemap.xml": urlSet},
+				GivenContentTypes: map[string]string{"https://example.com/sitemap.xml": "application/xml"},
+			},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/"},
+				{Scheme: "https", Host: "example.com", Path: "/about"},
+			},
+		},
+		{
+			name:        "given no robots.txt or sitemap, expect no URLs and no error",
+			givenSeed:   url.URL{Scheme: "https", Host: "example.com"},
+			givenClient: mockClient{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := NewLoader(test.givenClient).Discover(context.Background(), test.givenSeed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Path < actual[j].Path
+			})
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
+
+func TestLoader_Discover_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenClient   ClientProvider
+		expectedError error
+	}{
+		{
+			name: "given a robots.txt declaring a missing sitemap, expect a LoadError of that sitemap",
+			givenClient: mockClient{
+				GivenBodies: map[string]string{"https://example.com/robots.txt": "Sitemap: https://example.com/missing.xml\n"},
+			},
+			expectedError: &LoadError{
+				URL: url.URL{Scheme: "https", Host: "example.com", Path: "/missing.xml"},
+				Err: fmt.Errorf("%v: %w", errNotFound, ErrFailedToFetchSitemap),
+			},
+		},
+		{
+			name: "given a /sitemap.xml served as XML which cannot be parsed, expect a LoadError of it",
+			givenClient: mockClient{
+				GivenBodies:       map[string]string{"https://example.com/sitemap.xml": "<urlset>"},
+				GivenContentTypes: map[string]string{"https://example.com/sitemap.xml": "text/xml"},
+			},
+			expectedError: &LoadError{
+				URL: url.URL{Scheme: "https", Host: "example.com", Path: "/sitemap.xml"},
+				Err: fmt.Errorf("XML syntax error on line 1: unexpected EOF: %w", ErrFailedToParseSitemap),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewLoader(test.givenClient).Discover(context.Background(), url.URL{Scheme: "https", Host: "example.com"})
+
+			var loadErr *LoadError
+			if !errors.As(err, &loadErr) {
+				t.Fatalf("expected a LoadError, got %v", err)
+			}
+
+			if !cmp.Equal(loadErr.Error(), test.expectedError.Error()) {
+				t.Fatal(cmp.Diff(loadErr.Error(), test.expectedError.Error()))
+			}
+		})
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenReader  io.Reader
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given sitemap entries in any case, expect absolute URLs returned",
+			givenReader: strings.NewReader(`User-agent: *
+Sitemap: https://example.com/a.xml
+SITEMAP:https://example.com/b.xml
+Sitemap: /relative.xml
+`),
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/a.xml"},
+				{Scheme: "https", Host: "example.com", Path: "/b.xml"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := ParseRobots(test.givenReader)
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
+
+func gzipped(s string) string {
+	var b bytes.Buffer
+
+	w := gzip.NewWriter(&b)
+	_, _ = w.Write([]byte(s))
+	_ = w.Close()
+
+	return b.String()
+}
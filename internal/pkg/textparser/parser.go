@@ -0,0 +1,64 @@
+package textparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrFailedToReadText is returned if the body cannot be read.
+var (
+	ErrFailedToReadText = errors.New("failed to read text")
+)
+
+// urlPattern matches absolute http and https URLs within free text.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// trailingPunctuation is commonly written straight after a URL in prose but is not part of it.
+const trailingPunctuation = ".,;:!?)]}"
+
+// TextParser finds all absolute URLs within plain text.
+type TextParser struct {
+	URLBuilder URLBuilder
+}
+
+// New instantiates a TextParser.
+func New(builder URLBuilder) TextParser {
+	return TextParser{
+		URLBuilder: builder,
+	}
+}
+
+// URLBuilder removes the found url.URLs that should not be crawled and normalises the rest.
+type URLBuilder interface {
+	Clean([]*url.URL, *url.URL) []*url.URL
+}
+
+// FetchLinks finds all absolute http and https URLs within the text.
+func (t TextParser) FetchLinks(ctx context.Context, body io.Reader, baseURL *url.URL) ([]*url.URL, error) {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrFailedToReadText)
+	}
+
+	var found []*url.URL
+
+	for _, raw := range urlPattern.FindAllString(string(b), -1) {
+		u, err := url.Parse(strings.TrimRight(raw, trailingPunctuation))
+		if err != nil {
+			continue
+		}
+
+		found = append(found, u)
+	}
+
+	span := urlbuilder.StartSpan(ctx, "URLBuilder.Clean")
+	defer span.End()
+
+	return t.URLBuilder.Clean(found, baseURL), nil
+}
@@ -0,0 +1,54 @@
+package textparser
+
+import (
+	"context"
+	"crawler/internal/pkg/urlbuilder"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTextParser_FetchLinks_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenText    io.Reader
+		givenURL     *url.URL
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given text with URLs in prose, expect them returned without trailing punctuation",
+			givenText: strings.NewReader("See https://example.com/docs, or (https://example.com/faq).\n" +
+				"Mirror: http://example.org/docs\n"),
+			givenURL: &url.URL{Scheme: "https", Host: "example.com"},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/docs/"},
+				{Scheme: "https", Host: "example.com", Path: "/faq/"},
+			},
+		},
+		{
+			name:      "given text without URLs, expect none returned",
+			givenText: strings.NewReader("nothing to see here"),
+			givenURL:  &url.URL{Scheme: "https", Host: "example.com"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := New(urlbuilder.New()).FetchLinks(context.Background(), test.givenText, test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].Path < actual[j].Path
+			})
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
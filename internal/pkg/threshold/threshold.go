@@ -0,0 +1,155 @@
+package threshold
+
+import (
+	"crawler/internal/domain"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// Names of the thresholds which can be checked.
+const (
+	BrokenLinks   = "broken-links"
+	ServerErrors  = "server-errors"
+	RedirectChain = "redirect-chain"
+)
+
+// Exit codes of the crawler when a threshold is exceeded. 1 is left for every other failure.
+const (
+	ExitBrokenLinks   = 2
+	ExitServerErrors  = 3
+	ExitRedirectChain = 4
+)
+
+// Unchecked is the limit of a threshold which is not checked.
+const Unchecked = -1
+
+// Config sets the limit of each threshold, or Unchecked.
+type Config struct {
+	// MaxBrokenLinks is how many pages may fail to be fetched or respond with a 4xx or 5xx status.
+	MaxBrokenLinks int
+	// MaxServerErrors is how many pages may respond with a 5xx status.
+	MaxServerErrors int
+	// MaxRedirectChain is how many times a page may be redirected.
+	MaxRedirectChain int
+}
+
+// Check measures each threshold with a limit against the report, in the order BrokenLinks, ServerErrors then
+// RedirectChain.
+func Check(r domain.Report, cfg Config) []domain.Threshold {
+	var thresholds []domain.Threshold
+
+	if cfg.MaxBrokenLinks > Unchecked {
+		broken := Broken(r)
+
+		thresholds = append(thresholds, domain.Threshold{
+			Name:     BrokenLinks,
+			Limit:    cfg.MaxBrokenLinks,
+			Actual:   len(broken),
+			URLs:     broken,
+			ExitCode: ExitBrokenLinks,
+		})
+	}
+
+	if cfg.MaxServerErrors > Unchecked {
+		serverErrors := serverErrors(r.Pages)
+
+		thresholds = append(thresholds, domain.Threshold{
+			Name:     ServerErrors,
+			Limit:    cfg.MaxServerErrors,
+			Actual:   len(serverErrors),
+			URLs:     serverErrors,
+			ExitCode: ExitServerErrors,
+		})
+	}
+
+	if cfg.MaxRedirectChain > Unchecked {
+		longest, chained := redirectChains(r.Pages, cfg.MaxRedirectChain)
+
+		thresholds = append(thresholds, domain.Threshold{
+			Name:     RedirectChain,
+			Limit:    cfg.MaxRedirectChain,
+			Actual:   longest,
+			URLs:     chained,
+			ExitCode: ExitRedirectChain,
+		})
+	}
+
+	return thresholds
+}
+
+// Exceeded returns the first of the thresholds which was exceeded.
+func Exceeded(thresholds []domain.Threshold) (domain.Threshold, bool) {
+	for _, t := range thresholds {
+		if t.Exceeded() {
+			return t, true
+		}
+	}
+
+	return domain.Threshold{}, false
+}
+
+// Broken finds the pages which responded with a 4xx or 5xx status, or which could not be fetched at all, in order.
+// Pending pages were never requested, and sitemaps are not pages, so neither is broken.
+func Broken(r domain.Report) []url.URL {
+	broken := make(map[url.URL]bool)
+
+	for _, page := range r.Pages {
+		if page.StatusCode >= http.StatusBadRequest {
+			broken[page.URL] = true
+		}
+	}
+
+	for _, e := range r.Errors {
+		if e.Phase == domain.PhaseFetch {
+			broken[e.URL] = true
+		}
+	}
+
+	return sortURLs(broken)
+}
+
+func serverErrors(pages []domain.Page) []url.URL {
+	found := make(map[url.URL]bool)
+
+	for _, page := range pages {
+		if page.StatusCode >= http.StatusInternalServerError {
+			found[page.URL] = true
+		}
+	}
+
+	return sortURLs(found)
+}
+
+// redirectChains returns the longest redirect chain of any page, and the pages redirected more than maxRedirects
+// times.
+func redirectChains(pages []domain.Page, maxRedirects int) (int, []url.URL) {
+	longest := 0
+	chained := make(map[url.URL]bool)
+
+	for _, page := range pages {
+		if len(page.Redirects) > longest {
+			longest = len(page.Redirects)
+		}
+
+		if len(page.Redirects) > maxRedirects {
+			chained[page.URL] = true
+		}
+	}
+
+	return longest, sortURLs(chained)
+}
+
+func sortURLs(set map[url.URL]bool) []url.URL {
+	urls := make([]url.URL, 0, len(set))
+
+	for u := range set {
+		urls = append(urls, u)
+	}
+
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].String() < urls[j].String()
+	})
+
+	return urls
+}
@@ -0,0 +1,112 @@
+package threshold
+
+import (
+	"crawler/internal/domain"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheck(t *testing.T) {
+	report := domain.Report{
+		Pages: []domain.Page{
+			{URL: url.URL{Host: "example.com"}, StatusCode: 200},
+			{URL: url.URL{Host: "example.com", Path: "/missing/"}, StatusCode: 404},
+			{URL: url.URL{Host: "example.com", Path: "/down/"}, StatusCode: 503},
+			{URL: url.URL{Host: "example.com", Path: "/refused/"}},
+			{
+				URL:        url.URL{Host: "example.com", Path: "/old/"},
+				StatusCode: 200,
+				Redirects:  []url.URL{{Host: "example.com", Path: "/older/"}, {Host: "example.com", Path: "/new/"}},
+			},
+		},
+		Errors: []domain.CrawlError{
+			{URL: url.URL{Host: "example.com", Path: "/missing/"}, Phase: domain.PhaseFetch, Status: 404},
+			{URL: url.URL{Host: "example.com", Path: "/down/"}, Phase: domain.PhaseFetch, Status: 503},
+			{URL: url.URL{Host: "example.com", Path: "/refused/"}, Phase: domain.PhaseFetch, Err: errors.New("refused")},
+			{URL: url.URL{Host: "example.com"}, Phase: domain.PhaseParse, Err: errors.New("bad html")},
+			{URL: url.URL{Host: "example.com", Path: "/sitemap.xml"}, Phase: domain.PhaseSitemap, Status: 404},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		givenConfig        Config
+		expectedThresholds []domain.Threshold
+		expectedExceeded   string
+	}{
+		{
+			name:        "given no thresholds, expect none checked",
+			givenConfig: Config{MaxBrokenLinks: Unchecked, MaxServerErrors: Unchecked, MaxRedirectChain: Unchecked},
+		},
+		{
+			name:        "given every threshold is met, expect none exceeded",
+			givenConfig: Config{MaxBrokenLinks: 3, MaxServerErrors: 1, MaxRedirectChain: 2},
+			expectedThresholds: []domain.Threshold{
+				{
+					Name:   BrokenLinks,
+					Limit:  3,
+					Actual: 3,
+					URLs: []url.URL{
+						{Host: "example.com", Path: "/down/"},
+						{Host: "example.com", Path: "/missing/"},
+						{Host: "example.com", Path: "/refused/"},
+					},
+					ExitCode: ExitBrokenLinks,
+				},
+				{
+					Name:     ServerErrors,
+					Limit:    1,
+					Actual:   1,
+					URLs:     []url.URL{{Host: "example.com", Path: "/down/"}},
+					ExitCode: ExitServerErrors,
+				},
+				{
+					Name:     RedirectChain,
+					Limit:    2,
+					Actual:   2,
+					URLs:     []url.URL{},
+					ExitCode: ExitRedirectChain,
+				},
+			},
+		},
+		{
+			name:        "given no server errors or redirects are allowed, expect the server errors to be exceeded first",
+			givenConfig: Config{MaxBrokenLinks: Unchecked, MaxServerErrors: 0, MaxRedirectChain: 0},
+			expectedThresholds: []domain.Threshold{
+				{
+					Name:     ServerErrors,
+					Limit:    0,
+					Actual:   1,
+					URLs:     []url.URL{{Host: "example.com", Path: "/down/"}},
+					ExitCode: ExitServerErrors,
+				},
+				{
+					Name:     RedirectChain,
+					Limit:    0,
+					Actual:   2,
+					URLs:     []url.URL{{Host: "example.com", Path: "/old/"}},
+					ExitCode: ExitRedirectChain,
+				},
+			},
+			expectedExceeded: ServerErrors,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Check(report, test.givenConfig)
+
+			if !cmp.Equal(actual, test.expectedThresholds) {
+				t.Fatal(cmp.Diff(actual, test.expectedThresholds))
+			}
+
+			exceeded, _ := Exceeded(actual)
+
+			if !cmp.Equal(exceeded.Name, test.expectedExceeded) {
+				t.Fatal(cmp.Diff(exceeded.Name, test.expectedExceeded))
+			}
+		})
+	}
+}
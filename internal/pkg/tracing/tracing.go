@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// ErrUnknownExporter is returned if the exporter is neither stdout nor otlp.
+var (
+	ErrUnknownExporter = errors.New("unknown exporter, expected stdout or otlp")
+)
+
+// Exporters which spans can be sent to.
+const (
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// ServiceName identifies the crawler's spans.
+const ServiceName = "web-crawler"
+
+// Config chooses where spans are exported to.
+type Config struct {
+	// Exporter is either ExporterStdout or ExporterOTLP.
+	Exporter string
+	// Endpoint is the host and port of the OTLP HTTP collector, defaulting to localhost:4318.
+	Endpoint string
+	// Insecure sends spans to the OTLP collector without TLS.
+	Insecure bool
+	// Output is where the stdout exporter writes spans, defaulting to os.Stdout.
+	Output io.Writer
+}
+
+// New instantiates a TracerProvider which exports spans as configured. It must be shut down to flush the last spans.
+func New(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProvider(sdktrace.WithBatcher(exporter)), nil
+}
+
+// NewWithExporter instantiates a TracerProvider which exports each span to exporter as soon as it ends, such as a
+// tracetest.InMemoryExporter.
+func NewWithExporter(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return newProvider(sdktrace.WithSyncer(exporter))
+}
+
+func newProvider(processor sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		processor,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(ServiceName))),
+	)
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		output := cfg.Output
+		if output == nil {
+			output = os.Stdout
+		}
+
+		return stdouttrace.New(stdouttrace.WithWriter(output))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("%v: %w", cfg.Exporter, ErrUnknownExporter)
+	}
+}
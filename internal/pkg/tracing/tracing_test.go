@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNew_Stdout(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenSpan    string
+		expectedName string
+	}{
+		{
+			name:         "given a span, expect it written to the output once shut down",
+			givenSpan:    "crawl page",
+			expectedName: `"Name":"crawl page"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			tp, err := New(context.Background(), Config{Exporter: ExporterStdout, Output: &out})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, span := tp.Tracer("test").Start(context.Background(), test.givenSpan)
+			span.End()
+
+			err = tp.Shutdown(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(out.String(), test.expectedName) {
+				t.Fatalf("expected %v in %v", test.expectedName, out.String())
+			}
+		})
+	}
+}
+
+func TestNew_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenConfig   Config
+		expectedError error
+	}{
+		{
+			name:          "given an unknown exporter, expect error",
+			givenConfig:   Config{Exporter: "zipkin"},
+			expectedError: ErrUnknownExporter,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(context.Background(), test.givenConfig)
+			if err == nil {
+				t.Fatalf("expected %v, got nil", test.expectedError)
+			}
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestNewWithExporter(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenSpans    []string
+		expectedSpans []string
+	}{
+		{
+			name:          "given spans, expect each exported as soon as it ends",
+			givenSpans:    []string{"Client.Fetch", "Parser.Parse"},
+			expectedSpans: []string{"Client.Fetch", "Parser.Parse"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tracer := NewWithExporter(exporter).Tracer("test")
+
+			for _, name := range test.givenSpans {
+				_, span := tracer.Start(context.Background(), name)
+				span.End()
+			}
+
+			var actual []string
+			for _, span := range exporter.GetSpans() {
+				actual = append(actual, span.Name)
+			}
+
+			if !cmp.Equal(actual, test.expectedSpans) {
+				t.Fatal(cmp.Diff(actual, test.expectedSpans))
+			}
+		})
+	}
+}
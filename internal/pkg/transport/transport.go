@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"crawler/internal/pkg/dnscache"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Errors returned when a http.Transport is configured.
+var (
+	ErrInvalidProxy            = errors.New("invalid proxy, expected an http, https or socks5 URL")
+	ErrFailedToLoadCA          = errors.New("failed to load CA bundle")
+	ErrFailedToLoadCertificate = errors.New("failed to load client certificate")
+	ErrInvalidTLSVersion       = errors.New("invalid TLS version, expected 1.0, 1.1, 1.2 or 1.3")
+)
+
+// Defaults of the dialer of http.DefaultTransport, which are used unless Config overrides them.
+const (
+	defaultDialTimeout = 30 * time.Second
+	defaultKeepAlive   = 30 * time.Second
+)
+
+// versions maps the names of TLS versions to their values.
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Config chooses how connections are made. Any empty field keeps the default of http.DefaultTransport.
+type Config struct {
+	// Proxy is the URL of an http, https or socks5 proxy, with any credentials as its user info. Without it, the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.
+	Proxy string
+	// CABundle is a PEM file of certificates trusted as well as the system's.
+	CABundle string
+	// ClientCert and ClientKey are PEM files of a certificate presented to servers which ask for one.
+	ClientCert string
+	ClientKey  string
+	// InsecureSkipVerify accepts any certificate a server presents, so should only be used in development.
+	InsecureSkipVerify bool
+	// MinTLSVersion is the lowest TLS version accepted, e.g. 1.2.
+	MinTLSVersion string
+
+	// MaxIdleConns and MaxIdleConnsPerHost limit how many idle connections are kept for reuse, in total and to each
+	// host, and MaxConnsPerHost limits how many connections are open to each host at once.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	// IdleConnTimeout is how long an idle connection is kept for reuse.
+	IdleConnTimeout time.Duration
+	// DialTimeout, TLSHandshakeTimeout and ResponseHeaderTimeout limit how long connecting, the TLS handshake and
+	// waiting for the headers of a response take.
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	// KeepAlive is the interval between keep-alive probes of open connections, and DisableKeepAlives opens a new
+	// connection for every request.
+	KeepAlive         time.Duration
+	DisableKeepAlives bool
+	// DisableHTTP2 only uses HTTP/1.1, even with servers that support HTTP/2.
+	DisableHTTP2 bool
+	// DNSCacheTTL is how long the addresses of a host are reused before they are looked up again. Zero looks them
+	// up for every connection.
+	DNSCacheTTL time.Duration
+}
+
+// New configures a http.Transport from cfg.
+func New(cfg Config) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxy, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrInvalidProxy)
+		}
+
+		if proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5" {
+			return nil, fmt.Errorf("%v: %w", proxy.Scheme, ErrInvalidProxy)
+		}
+
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	t.TLSClientConfig = tlsConfig
+
+	tune(t, cfg)
+
+	return t, nil
+}
+
+// tune applies the connection pooling, timeout, keep-alive, HTTP/2 and DNS settings of cfg.
+func tune(t *http.Transport, cfg Config) {
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	if cfg.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	if cfg.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+
+	if cfg.ResponseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	}
+
+	t.DisableKeepAlives = cfg.DisableKeepAlives
+
+	if cfg.DisableHTTP2 {
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   defaultDialTimeout,
+		KeepAlive: defaultKeepAlive,
+	}
+
+	if cfg.DialTimeout > 0 {
+		dialer.Timeout = cfg.DialTimeout
+	}
+
+	if cfg.KeepAlive != 0 {
+		dialer.KeepAlive = cfg.KeepAlive
+	}
+
+	t.DialContext = dialer.DialContext
+
+	if cfg.DNSCacheTTL > 0 {
+		t.DialContext = dnscache.New(net.DefaultResolver, dialer, cfg.DNSCacheTTL).DialContext
+	}
+}
+
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{ //nolint:gosec // verification and the minimum version are configured by the user.
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.MinTLSVersion != "" {
+		version, ok := versions[cfg.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("%v: %w", cfg.MinTLSVersion, ErrInvalidTLSVersion)
+		}
+
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrFailedToLoadCA)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%v has no PEM certificates: %w", cfg.CABundle, ErrFailedToLoadCA)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrFailedToLoadCertificate)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// VersionName describes a TLS version, e.g. TLS 1.3.
+func VersionName(version uint16) string {
+	for name, v := range versions {
+		if v == version {
+			return "TLS " + name
+		}
+	}
+
+	return fmt.Sprintf("0x%04x", version)
+}
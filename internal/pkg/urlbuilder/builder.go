@@ -1,9 +1,11 @@
 package urlbuilder
 
 import (
+	"crawler/internal/domain"
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/antchfx/htmlquery"
@@ -26,7 +28,7 @@ func New() Builder {
 
 // Build takes the found hrefs from a http.Response body and builds desired url.URL's.
 func (b Builder) Build(nodes []*html.Node, baseURL *url.URL) ([]*url.URL, error) {
-	urls := make(map[url.URL]*url.URL)
+	var found []*url.URL
 
 	for _, node := range nodes {
 		n := htmlquery.SelectAttr(node, "href")
@@ -36,12 +38,47 @@ func (b Builder) Build(nodes []*html.Node, baseURL *url.URL) ([]*url.URL, error)
 			return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseURL)
 		}
 
-		if u.Hostname() != baseURL.Hostname() && u.IsAbs() {
+		found = append(found, u)
+	}
+
+	return b.Clean(found, baseURL), nil
+}
+
+// BuildLinks takes the found anchors from a http.Response body and builds a domain.Link for each that should be
+// crawled, keeping how the anchor was presented. Unlike Build, an anchor to a URL already found is kept, as it is a
+// separate link.
+func (b Builder) BuildLinks(anchors []*html.Node, baseURL *url.URL) ([]domain.Link, error) {
+	var found []domain.Link
+
+	for _, anchor := range anchors {
+		u, err := url.Parse(strings.TrimSpace(htmlquery.SelectAttr(anchor, "href")))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, ErrFailedToParseURL)
+		}
+
+		if !allowed(u, baseURL) {
 			continue
 		}
 
-		// This is needed as we don't want to crawl pages that are protected by Cloudflare.
-		if strings.Contains(u.Path, "cdn-cgi") {
+		found = append(found, domain.Link{
+			URL:      *cleanUpURL(u, baseURL),
+			Text:     anchorText(anchor),
+			Title:    normalise(htmlquery.SelectAttr(anchor, "title")),
+			Rel:      rel(anchor),
+			Target:   strings.TrimSpace(htmlquery.SelectAttr(anchor, "target")),
+			Location: location(anchor),
+		})
+	}
+
+	return found, nil
+}
+
+// Clean removes the url.URL's that should not be crawled and normalises the rest, the same way as found hrefs.
+func (b Builder) Clean(found []*url.URL, baseURL *url.URL) []*url.URL {
+	urls := make(map[url.URL]*url.URL)
+
+	for _, u := range found {
+		if !allowed(u, baseURL) {
 			continue
 		}
 
@@ -53,7 +90,75 @@ func (b Builder) Build(nodes []*html.Node, baseURL *url.URL) ([]*url.URL, error)
 		}
 	}
 
-	return mapToArray(urls), nil
+	return mapToArray(urls)
+}
+
+// allowed reports whether a found url.URL should be crawled.
+func allowed(u, baseURL *url.URL) bool {
+	if u.Hostname() != baseURL.Hostname() && u.IsAbs() {
+		return false
+	}
+
+	// This is needed as we don't want to crawl pages that are protected by Cloudflare.
+	return !strings.Contains(u.Path, "cdn-cgi")
+}
+
+// anchorText is the text of an anchor or, if it has none, its aria-label or the alt text of its images.
+func anchorText(anchor *html.Node) string {
+	if text := normalise(htmlquery.InnerText(anchor)); text != "" {
+		return text
+	}
+
+	if label := normalise(htmlquery.SelectAttr(anchor, "aria-label")); label != "" {
+		return label
+	}
+
+	var alts []string
+
+	for _, img := range htmlquery.Find(anchor, ".//img[@alt]") {
+		if alt := normalise(htmlquery.SelectAttr(img, "alt")); alt != "" {
+			alts = append(alts, alt)
+		}
+	}
+
+	return strings.Join(alts, " ")
+}
+
+// rel returns the lower case values of an anchor's rel attribute, or nil if it has none.
+func rel(anchor *html.Node) []string {
+	values := strings.Fields(strings.ToLower(htmlquery.SelectAttr(anchor, "rel")))
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values
+}
+
+// location finds the part of the page an anchor is in from its closest sectioning ancestor.
+func location(anchor *html.Node) domain.Location {
+	for n := anchor.Parent; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+
+		switch n.Data {
+		case "nav":
+			return domain.LocationNav
+		case "header":
+			return domain.LocationHeader
+		case "footer":
+			return domain.LocationFooter
+		case "aside":
+			return domain.LocationAside
+		}
+	}
+
+	return domain.LocationBody
+}
+
+// normalise collapses all runs of whitespace into single spaces.
+func normalise(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 func cleanUpURL(u, baseURL *url.URL) *url.URL {
@@ -61,7 +166,8 @@ func cleanUpURL(u, baseURL *url.URL) *url.URL {
 
 	u = baseURL.ResolveReference(u)
 
-	if !strings.HasSuffix(u.Path, "/") {
+	// Files such as stylesheets, images and PDFs keep their path, as a trailing slash would make them a different URL.
+	if !strings.HasSuffix(u.Path, "/") && path.Ext(u.Path) == "" {
 		u.Path = fmt.Sprintf("%v/", u.Path)
 	}
 
@@ -1,10 +1,14 @@
 package urlbuilder
 
 import (
+	"crawler/internal/domain"
 	"net/url"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/antchfx/htmlquery"
+
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/google/go-cmp/cmp"
@@ -45,12 +49,25 @@ func TestBuilder_Build_Success(t *testing.T) {
 						},
 					},
 				},
+				{
+					Attr: []html.Attribute{
+						{
+							Key: "href",
+							Val: "/files/report.pdf",
+						},
+					},
+				},
 			},
 			givenURL: &url.URL{
 				Scheme: "https",
 				Host:   "example.com",
 			},
 			expectedURLs: []*url.URL{
+				{
+					Scheme: "https",
+					Host:   "example.com",
+					Path:   "/files/report.pdf",
+				},
 				{
 					Scheme: "https",
 					Host:   "example.com",
@@ -133,3 +150,108 @@ func TestBuilder_Build_Fail(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_Clean(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenURLs    []*url.URL
+		givenURL     *url.URL
+		expectedURLs []*url.URL
+	}{
+		{
+			name: "given URLs from a sitemap, expect other hosts removed and the rest normalised",
+			givenURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/about", RawQuery: "ref=sitemap"},
+				{Scheme: "https", Host: "example.com", Path: "/about/"},
+				{Scheme: "https", Host: "example.org", Path: "/elsewhere"},
+				{Scheme: "https", Host: "example.com", Path: "/cdn-cgi/l/email-protection"},
+			},
+			givenURL: &url.URL{
+				Scheme: "https",
+				Host:   "example.com",
+			},
+			expectedURLs: []*url.URL{
+				{Scheme: "https", Host: "example.com", Path: "/about/"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New().Clean(test.givenURLs, test.givenURL)
+
+			if !cmp.Equal(actual, test.expectedURLs) {
+				t.Fatal(cmp.Diff(actual, test.expectedURLs))
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildLinks(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenHTML     string
+		givenURL      *url.URL
+		expectedLinks []domain.Link
+	}{
+		{
+			name: "given anchors in different parts of a page, expect their text, attributes and location kept",
+			givenHTML: `<html><body><nav><ul><li><a href="/about" title=" About us ">About</a></li></ul></nav>
+				<main><p>Read <a href="/about?ref=body" rel="Nofollow noopener" target="_blank">more
+				about us</a></p><a href="/home"><img src="/logo.png" alt="Home"></a><a href="/x" aria-label="Close"></a>
+				<a href="/empty"> </a><a href="https://example.org/">Elsewhere</a></main>
+				<footer><a href="/contact">Contact</a></footer></body></html>`,
+			givenURL: &url.URL{Scheme: "https", Host: "example.com"},
+			expectedLinks: []domain.Link{
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/about/"},
+					Text:     "About",
+					Title:    "About us",
+					Location: domain.LocationNav,
+				},
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/about/"},
+					Text:     "more about us",
+					Rel:      []string{"nofollow", "noopener"},
+					Target:   "_blank",
+					Location: domain.LocationBody,
+				},
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/home/"},
+					Text:     "Home",
+					Location: domain.LocationBody,
+				},
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/x/"},
+					Text:     "Close",
+					Location: domain.LocationBody,
+				},
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/empty/"},
+					Location: domain.LocationBody,
+				},
+				{
+					URL:      url.URL{Scheme: "https", Host: "example.com", Path: "/contact/"},
+					Text:     "Contact",
+					Location: domain.LocationFooter,
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := htmlquery.Parse(strings.NewReader(test.givenHTML))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := New().BuildLinks(htmlquery.Find(doc, "//a[@href]"), test.givenURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedLinks) {
+				t.Fatal(cmp.Diff(actual, test.expectedLinks))
+			}
+		})
+	}
+}
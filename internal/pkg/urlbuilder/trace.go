@@ -0,0 +1,18 @@
+package urlbuilder
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans of building URLs.
+const tracerName = "crawler/internal/pkg/urlbuilder"
+
+// StartSpan starts a span named name for the time spent building the URLs of a page, as a child of the span in ctx.
+// It is recorded by the provider of that span, so it is only exported if the page is traced.
+func StartSpan(ctx context.Context, name string) trace.Span {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, name)
+
+	return span
+}
@@ -0,0 +1,74 @@
+package file
+
+import (
+	"crawler/storage"
+	"crawler/storage/memory"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Errors returned from file storage.
+var (
+	ErrFailedToLoad = errors.New("failed to load pages")
+	ErrFailedToSave = errors.New("failed to save pages")
+)
+
+// File is a storage method which stores storage.Page's in-memory and saves them to a JSON file, so they are kept
+// between crawls.
+type File struct {
+	*memory.Memory
+	path string
+}
+
+// New instantiates an empty File which saves to path.
+func New(path string) *File {
+	return &File{
+		Memory: memory.New(),
+		path:   path,
+	}
+}
+
+// Load reads every storage.Page saved to the File's path. A missing file is treated as having no pages.
+func (f *File) Load() error {
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrFailedToLoad)
+	}
+
+	var pages []storage.Page
+
+	err = json.Unmarshal(b, &pages)
+	if err != nil {
+		return fmt.Errorf("%v: %v: %w", f.path, err, ErrFailedToLoad)
+	}
+
+	for _, page := range pages {
+		err = f.Insert(page)
+		if err != nil {
+			return fmt.Errorf("%v: %v: %w", page.URL.String(), err, ErrFailedToLoad)
+		}
+	}
+
+	return nil
+}
+
+// Save writes every storage.Page to the File's path, replacing what was saved before.
+func (f *File) Save() error {
+	b, err := json.Marshal(f.All())
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrFailedToSave)
+	}
+
+	err = os.WriteFile(f.path, b, 0600)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrFailedToSave)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package file
+
+import (
+	"crawler/storage"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestFile_Save_Load(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenPages []storage.Page
+	}{
+		{
+			name: "given saved pages, expect the same pages loaded",
+			givenPages: []storage.Page{
+				{
+					URL:          url.URL{Scheme: "https", Host: "example.com", Path: "/"},
+					CrawledAt:    time.Date(2021, 6, 10, 16, 0, 0, 0, time.UTC),
+					StatusCode:   200,
+					ETag:         `"abc"`,
+					LastModified: "Thu, 10 Jun 2021 16:00:00 GMT",
+					Links:        []url.URL{{Scheme: "https", Host: "example.com", Path: "/about/"}},
+				},
+				{
+					URL: url.URL{Scheme: "https", Host: "example.com", Path: "/about/"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+
+			saved := New(path)
+			for _, page := range test.givenPages {
+				if err := saved.Insert(page); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := saved.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := New(path)
+			if err := loaded.Load(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(loaded.All(), test.givenPages) {
+				t.Fatal(cmp.Diff(loaded.All(), test.givenPages))
+			}
+		})
+	}
+}
+
+func TestFile_Load(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenContent  *string
+		expectedPages []storage.Page
+		expectedError error
+	}{
+		{
+			name:          "given no file, expect no pages",
+			expectedPages: []storage.Page{},
+		},
+		{
+			name:          "given a file that is not JSON, expect error",
+			givenContent:  stringPointer("not json"),
+			expectedError: ErrFailedToLoad,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache.json")
+
+			if test.givenContent != nil {
+				if err := os.WriteFile(path, []byte(*test.givenContent), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f := New(path)
+
+			err := f.Load()
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+
+			if test.expectedError == nil && !cmp.Equal(f.All(), test.expectedPages) {
+				t.Fatal(cmp.Diff(f.All(), test.expectedPages))
+			}
+		})
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
@@ -4,6 +4,7 @@ import (
 	"crawler/storage"
 	"errors"
 	"net/url"
+	"sort"
 	"sync"
 )
 
@@ -51,3 +52,34 @@ func (m *Memory) Insert(page storage.Page) error {
 
 	return nil
 }
+
+// Update replaces an existing storage.Page in-memory. Error if no key can be found.
+func (m *Memory) Update(page storage.Page) error {
+	m.Lock()
+	defer m.Unlock()
+	_, ok := m.pages[page.URL]
+	if !ok {
+		return ErrInvalidKey
+	}
+
+	m.pages[page.URL] = page
+
+	return nil
+}
+
+// All returns every storage.Page in-memory, ordered by URL.
+func (m *Memory) All() []storage.Page {
+	m.RLock()
+	defer m.RUnlock()
+
+	pages := make([]storage.Page, 0, len(m.pages))
+	for _, page := range m.pages {
+		pages = append(pages, page)
+	}
+
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].URL.String() < pages[j].URL.String()
+	})
+
+	return pages
+}
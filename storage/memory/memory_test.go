@@ -161,3 +161,113 @@ func TestMemory_Insert_Fail(t *testing.T) {
 		})
 	}
 }
+
+func TestMemory_Update_Success(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenPage    storage.Page
+		givenMemory  *Memory
+		expectedPage storage.Page
+	}{
+		{
+			name: "given a page that exists, replace it",
+			givenPage: storage.Page{
+				URL:        url.URL{Host: "example.com", Path: "/test/"},
+				StatusCode: 200,
+			},
+			givenMemory: &Memory{
+				pages: map[url.URL]storage.Page{
+					{Host: "example.com", Path: "/test/"}: {
+						URL: url.URL{Host: "example.com", Path: "/test/"},
+					},
+				},
+			},
+			expectedPage: storage.Page{
+				URL:        url.URL{Host: "example.com", Path: "/test/"},
+				StatusCode: 200,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.givenMemory.Update(test.givenPage)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := test.givenMemory.Get(test.givenPage.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !cmp.Equal(actual, test.expectedPage) {
+				t.Fatal(cmp.Diff(actual, test.expectedPage))
+			}
+		})
+	}
+}
+
+func TestMemory_Update_Fail(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenPage     storage.Page
+		givenMemory   *Memory
+		expectedError error
+	}{
+		{
+			name: "given a page that does not exist, return error",
+			givenPage: storage.Page{
+				URL: url.URL{Host: "example.com", Path: "/test/"},
+			},
+			givenMemory: &Memory{
+				pages: map[url.URL]storage.Page{},
+			},
+			expectedError: ErrInvalidKey,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.givenMemory.Update(test.givenPage)
+
+			if !cmp.Equal(err, test.expectedError, cmpopts.EquateErrors()) {
+				t.Fatal(cmp.Diff(err, test.expectedError, cmpopts.EquateErrors()))
+			}
+		})
+	}
+}
+
+func TestMemory_All(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenMemory   *Memory
+		expectedPages []storage.Page
+	}{
+		{
+			name: "given pages, expect all of them ordered by URL",
+			givenMemory: &Memory{
+				pages: map[url.URL]storage.Page{
+					{Host: "example.com", Path: "/b/"}: {URL: url.URL{Host: "example.com", Path: "/b/"}},
+					{Host: "example.com", Path: "/a/"}: {URL: url.URL{Host: "example.com", Path: "/a/"}},
+				},
+			},
+			expectedPages: []storage.Page{
+				{URL: url.URL{Host: "example.com", Path: "/a/"}},
+				{URL: url.URL{Host: "example.com", Path: "/b/"}},
+			},
+		},
+		{
+			name:          "given no pages, expect none",
+			givenMemory:   New(),
+			expectedPages: []storage.Page{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.givenMemory.All()
+
+			if !cmp.Equal(actual, test.expectedPages) {
+				t.Fatal(cmp.Diff(actual, test.expectedPages))
+			}
+		})
+	}
+}
@@ -7,7 +7,52 @@ import (
 
 // Page is the storage representation of domain.Page.
 type Page struct {
-	URL       url.URL
-	Referrer  url.URL
-	CrawledAt time.Time
+	URL           url.URL
+	Referrer      url.URL
+	Seed          url.URL
+	Source        string
+	Depth         int
+	CrawledAt     time.Time
+	FetchDuration time.Duration
+	StatusCode    int
+	Redirects     []url.URL
+	ContentType   string
+	ETag          string
+	LastModified  string
+	Unchanged     bool
+	Truncated     bool
+	SkipReason    string
+	Metadata      Metadata
+	ContentHash   string
+	SimHash       uint64
+	Outlinks      []Link
+	Links         []url.URL
+}
+
+// Link is the storage representation of domain.Link.
+type Link struct {
+	URL      url.URL
+	Text     string
+	Title    string
+	Rel      []string
+	Target   string
+	Location string
+}
+
+// Metadata is the storage representation of domain.Metadata.
+type Metadata struct {
+	Title       string
+	Description string
+	Canonical   url.URL
+	Alternates  []Alternate
+	H1          []string
+	H2          []string
+	Lang        string
+	WordCount   int
+}
+
+// Alternate is the storage representation of domain.Alternate.
+type Alternate struct {
+	Lang string
+	URL  url.URL
 }